@@ -0,0 +1,73 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/effect"
+)
+
+// AOTCache creates an ahead-of-time cache by recording a training run of the application at build time and then
+// creating the cache from the recorded configuration. AOT caches require Java 24+.
+type AOTCache struct {
+	TrainingRun
+}
+
+func NewAOTCache(applicationPath string, exec effect.Executor, jvmLayer string, jdkDigest string, trainingArgs []string, info libcnb.BuildpackInfo) AOTCache {
+	return AOTCache{
+		TrainingRun: NewTrainingRun("AOT Cache", applicationPath, exec, jvmLayer, jdkDigest, trainingArgs, info),
+	}
+}
+
+func (a AOTCache) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	return a.TrainingRun.Contribute(layer, func(layer libcnb.Layer, jvm string) (libcnb.Layer, error) {
+		configuration := filepath.Join(layer.Path, "application.aotconf")
+		cache := filepath.Join(layer.Path, "application.aot")
+
+		a.Logger.Bodyf("Recording AOT configuration with training run")
+		if err := a.Run(layer, "-XX:AOTMode=record", fmt.Sprintf("-XX:AOTConfiguration=%s", configuration)); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to record AOT configuration\n%w", err)
+		}
+
+		a.Logger.Bodyf("Creating AOT cache")
+		if err := a.Run(layer, "-XX:AOTMode=create", fmt.Sprintf("-XX:AOTConfiguration=%s", configuration),
+			fmt.Sprintf("-XX:AOTCache=%s", cache)); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create AOT cache\n%w", err)
+		}
+
+		if _, err := os.Stat(cache); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("training run did not create AOT cache %s\n%w", cache, err)
+		}
+
+		if err := os.RemoveAll(configuration); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to remove %s\n%w", configuration, err)
+		}
+
+		layer.LaunchEnvironment.Default("BPI_JVM_AOT_CACHE", cache)
+		layer.LaunchEnvironment.Default("BPI_JVM_AOT_JVM_FINGERPRINT", jvm)
+
+		return layer, nil
+	})
+}
+
+func (a AOTCache) Name() string {
+	return "aot-cache"
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/effect"
+	"github.com/paketo-buildpacks/libpak/effect/mocks"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testAOTCache(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		ctx  libcnb.BuildContext
+		exec *mocks.Executor
+	)
+
+	it.Before(func() {
+		ctx.Application.Path = t.TempDir()
+		ctx.Layers.Path = t.TempDir()
+		exec = &mocks.Executor{}
+
+		Expect(os.MkdirAll(filepath.Join(ctx.Layers.Path, "jre"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Layers.Path, "jre", "release"), []byte(`JAVA_VERSION="24.0.1"`), 0644)).To(Succeed())
+
+		exec.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+			for _, a := range args.Get(0).(effect.Execution).Args {
+				if strings.HasPrefix(a, "-XX:AOTConfiguration=") || strings.HasPrefix(a, "-XX:AOTCache=") {
+					Expect(os.WriteFile(strings.SplitN(a, "=", 2)[1], []byte{}, 0644)).To(Succeed())
+				}
+			}
+		}).Return(nil)
+	})
+
+	it("contributes AOT cache", func() {
+		a := libjvm.NewAOTCache(ctx.Application.Path, exec, "jre", "test-sha256", []string{"test.Main"}, ctx.Buildpack.Info)
+		a.Logger = bard.NewLogger(io.Discard)
+
+		layer, err := ctx.Layers.Layer("aot-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = a.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		f, err := libjvm.JVMFingerprint(filepath.Join(ctx.Layers.Path, "jre"))
+		Expect(err).NotTo(HaveOccurred())
+
+		configuration := filepath.Join(layer.Path, "application.aotconf")
+		cache := filepath.Join(layer.Path, "application.aot")
+
+		Expect(exec.Calls).To(HaveLen(2))
+		Expect(exec.Calls[0].Arguments[0].(effect.Execution).Args).To(Equal([]string{
+			"-XX:AOTMode=record", "-XX:AOTConfiguration=" + configuration, "test.Main",
+		}))
+		Expect(exec.Calls[1].Arguments[0].(effect.Execution).Args).To(Equal([]string{
+			"-XX:AOTMode=create", "-XX:AOTConfiguration=" + configuration, "-XX:AOTCache=" + cache, "test.Main",
+		}))
+
+		Expect(configuration).NotTo(BeAnExistingFile())
+		Expect(cache).To(BeARegularFile())
+		Expect(layer.Metadata).To(HaveKeyWithValue("jdk-digest", "test-sha256"))
+		Expect(layer.LaunchEnvironment["BPI_JVM_AOT_CACHE.default"]).To(Equal(cache))
+		Expect(layer.LaunchEnvironment["BPI_JVM_AOT_JVM_FINGERPRINT.default"]).To(Equal(f))
+	})
+
+	it("reuses AOT cache unless the JDK changes", func() {
+		contribute := func(layer libcnb.Layer, digest string) libcnb.Layer {
+			a := libjvm.NewAOTCache(ctx.Application.Path, exec, "jre", digest, []string{"test.Main"}, ctx.Buildpack.Info)
+			a.Logger = bard.NewLogger(io.Discard)
+
+			layer, err := a.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+			return layer
+		}
+
+		layer, err := ctx.Layers.Layer("aot-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer = contribute(layer, "test-sha256")
+
+		// a launch-only JVM layer is not restored on rebuild
+		Expect(os.RemoveAll(filepath.Join(ctx.Layers.Path, "jre"))).To(Succeed())
+		layer = contribute(layer, "test-sha256")
+		Expect(exec.Calls).To(HaveLen(2))
+
+		Expect(os.MkdirAll(filepath.Join(ctx.Layers.Path, "jre"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Layers.Path, "jre", "release"), []byte(`JAVA_VERSION="24.0.2"`), 0644)).To(Succeed())
+		contribute(layer, "another-sha256")
+		Expect(exec.Calls).To(HaveLen(4))
+	})
+
+	it("fails if the training run does not create a cache", func() {
+		exec = &mocks.Executor{}
+		exec.On("Execute", mock.Anything).Return(nil)
+
+		a := libjvm.NewAOTCache(ctx.Application.Path, exec, "jre", "test-sha256", []string{"test.Main"}, ctx.Buildpack.Info)
+		a.Logger = bard.NewLogger(io.Discard)
+
+		layer, err := ctx.Layers.Layer("aot-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = a.Contribute(layer)
+		Expect(err).To(MatchError(ContainSubstring("training run did not create AOT cache")))
+	})
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package libjvm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/heroku/color"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+// SourceBuildFiles are the build files of an application that is compiled by a later buildpack, which replaces the
+// application with the compiled one.
+var SourceBuildFiles = []string{"pom.xml", "build.gradle", "build.gradle.kts", "build.sbt", "project.clj"}
+
+// ApplicationClassCount counts the classes in the application, and estimates the threads it uses, at build time so that
+// the memory calculator does not have to walk the application each time it starts.  A thread count of 0 means that no
+// estimate could be made. The count is keyed by a fingerprint of the application so that
+// it is only reused while the application is unchanged.  Applications built from source are not counted, see
+// IsSourceApplication, as they are replaced after the count is made.
+type ApplicationClassCount struct {
+	ApplicationPath  string
+	LayerContributor libpak.LayerContributor
+	Logger           bard.Logger
+}
+
+func NewApplicationClassCount(applicationPath string, info libcnb.BuildpackInfo) ApplicationClassCount {
+	return ApplicationClassCount{
+		ApplicationPath: applicationPath,
+		LayerContributor: libpak.NewLayerContributor(
+			"Application Class Count",
+			info,
+			libcnb.LayerTypes{
+				Launch: true,
+			},
+		),
+	}
+}
+
+func (a ApplicationClassCount) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	a.LayerContributor.Logger = a.Logger
+
+	f, err := count.Fingerprint(a.ApplicationPath)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to fingerprint application\n%w", err)
+	}
+	a.LayerContributor.ExpectedMetadata = map[string]interface{}{"fingerprint": f}
+
+	return a.LayerContributor.Contribute(layer, func() (libcnb.Layer, error) {
+		c, skipped, err := count.ClassesAndSkippedJars(a.ApplicationPath)
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to count application classes\n%w", err)
+		}
+		a.Logger.Bodyf("Counted %d application classes", c)
+		if skipped > 0 {
+			a.Logger.Body(color.New(color.Faint, color.Bold).Sprintf(
+				"WARNING: Skipped %d compressed nested JARs too large to count, the class count may be too low", skipped))
+		}
+
+		e, err := EstimateThreadCount(a.ApplicationPath)
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to estimate application thread count\n%w", err)
+		}
+		if len(e.Evidence) > 0 {
+			a.Logger.Bodyf("Estimated %d application threads from %s", e.Count, strings.Join(e.Evidence, ", "))
+		}
+
+		layer.LaunchEnvironment.Default("BPI_APPLICATION_CLASS_COUNT", c)
+		layer.LaunchEnvironment.Default("BPI_APPLICATION_THREAD_COUNT", e.Count)
+		layer.LaunchEnvironment.Default("BPI_APPLICATION_THREAD_COUNT_EVIDENCE", strings.Join(e.Evidence, ThreadCountEvidenceSeparator))
+		layer.LaunchEnvironment.Default("BPI_APPLICATION_FINGERPRINT", f)
+
+		return layer, nil
+	})
+}
+
+// IsSourceApplication returns whether the application contains any of the SourceBuildFiles, and so is replaced by a
+// later buildpack once it has been compiled.
+func IsSourceApplication(applicationPath string) (bool, error) {
+	for _, f := range SourceBuildFiles {
+		file := filepath.Join(applicationPath, f)
+		if _, err := os.Stat(file); err == nil {
+			return true, nil
+		} else if !os.IsNotExist(err) {
+			return false, fmt.Errorf("unable to stat %s\n%w", file, err)
+		}
+	}
+
+	return false, nil
+}
+
+func (a ApplicationClassCount) Name() string {
+	return "application-class-count"
+}
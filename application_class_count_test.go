@@ -0,0 +1,79 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package libjvm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+func testApplicationClassCount(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		ctx libcnb.BuildContext
+	)
+
+	it.Before(func() {
+		ctx.Buildpack.Info.Name = "test-name"
+		ctx.Application.Path = t.TempDir()
+		ctx.Layers.Path = t.TempDir()
+
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "alpha.class"), []byte{}, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "bravo.class"), []byte{}, 0644)).To(Succeed())
+	})
+
+	it("contributes application class count", func() {
+		a := libjvm.NewApplicationClassCount(ctx.Application.Path, ctx.Buildpack.Info)
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = a.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		f, err := count.Fingerprint(ctx.Application.Path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LayerTypes.Launch).To(BeTrue())
+		Expect(layer.Metadata).To(HaveKeyWithValue("fingerprint", f))
+		Expect(layer.LaunchEnvironment["BPI_APPLICATION_CLASS_COUNT.default"]).To(Equal("2"))
+		Expect(layer.LaunchEnvironment["BPI_APPLICATION_FINGERPRINT.default"]).To(Equal(f))
+		Expect(layer.LaunchEnvironment["BPI_APPLICATION_THREAD_COUNT.default"]).To(Equal("0"))
+	})
+
+	it("contributes application thread count estimate", func() {
+		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-webflux-6.1.0.jar"), []byte{}, 0644)).To(Succeed())
+
+		a := libjvm.NewApplicationClassCount(ctx.Application.Path, ctx.Buildpack.Info)
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = a.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LaunchEnvironment["BPI_APPLICATION_THREAD_COUNT.default"]).To(Equal("100"))
+		Expect(layer.LaunchEnvironment["BPI_APPLICATION_THREAD_COUNT_EVIDENCE.default"]).To(Equal("spring-webflux-6.1.0.jar"))
+	})
+}
@@ -19,6 +19,8 @@ package libjvm
 import (
 	"fmt"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/mattn/go-shellwords"
 	"github.com/paketo-buildpacks/libpak/effect"
@@ -112,6 +114,12 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 	}
 
 	jvmVersion := NewJVMVersion(b.Logger)
+	vendor, err := jvmVersion.GetJVMVendor(context.Application.Path, cr)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to determine jvm vendor\n%w", err)
+	}
+	dr.Dependencies = PreferVendor(dr.Dependencies, vendor)
+
 	v, err := jvmVersion.GetJVMVersion(context.Application.Path, cr, dr)
 	if err != nil {
 		return libcnb.BuildResult{}, fmt.Errorf("unable to determine jvm version\n%w", err)
@@ -138,6 +146,24 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 		jreMissing = true
 	}
 
+	// only check the vendor of a JVM that will be provided, missing versions are reported below
+	if vendor != "" && (!jdkMissing || !jreMissing) {
+		if err := b.checkVendor(cr, vendor, context.Buildpack.Info, depJDK, depJRE); err != nil {
+			return libcnb.BuildResult{}, err
+		}
+	}
+
+	// a training run on rebuild needs the JVM's files, which are only restored if its layer is cached
+	if jreRequired && (cr.ResolveBool("BP_JVM_CDS_ENABLED") || cr.ResolveBool("BP_JVM_AOT_CACHE_ENABLED")) {
+		metadata := map[string]interface{}{"build": true, "cache": true}
+		for k, v := range jrePlanEntry.Metadata {
+			if k != "build" && k != "cache" {
+				metadata[k] = v
+			}
+		}
+		jrePlanEntry.Metadata = metadata
+	}
+
 	if t, _ := cr.Resolve("BP_JVM_TYPE"); strings.ToLower(t) == "jdk" {
 		jreSkipped = true
 	}
@@ -165,7 +191,7 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 
 	// jLink
 	if jLinkEnabled {
-		if IsBeforeJava9(v) {
+		if IsBeforeJava9(depJDK.Version) {
 			return libcnb.BuildResult{}, fmt.Errorf("unable to build, jlink is compatible with Java 9+ only\n")
 		}
 		if err = b.contributeJDK(depJDK); err != nil {
@@ -174,7 +200,12 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 		if err = b.contributeJLink(cr, jrePlanEntry.Metadata, context.Application.Path, depJDK); err != nil {
 			return libcnb.BuildResult{}, fmt.Errorf("unable to contribute Jlink\n%w", err)
 		}
-		b.contributeHelpers(context, depJDK)
+		if err = b.contributeHelpers(context, depJDK, cr); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to contribute helpers\n%w", err)
+		}
+		if err = b.contributeArchives(cr, context, depJDK); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to contribute class archives\n%w", err)
+		}
 		return b.Result, nil
 	}
 
@@ -187,7 +218,12 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 		if err = b.contributeJDKAsJRE(depJDK, jrePlanEntry, context); err != nil {
 			return libcnb.BuildResult{}, fmt.Errorf("unable to contribute JDK as JRE\n%w", err)
 		}
-		b.contributeHelpers(context, depJDK)
+		if err = b.contributeHelpers(context, depJDK, cr); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to contribute helpers\n%w", err)
+		}
+		if err = b.contributeArchives(cr, context, depJDK); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to contribute class archives\n%w", err)
+		}
 		return b.Result, nil
 	}
 
@@ -205,13 +241,49 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 			return libcnb.BuildResult{}, fmt.Errorf("unable to contribute JDK \n%w", err)
 		}
 		if IsLaunchContribution(jrePlanEntry.Metadata) {
-			b.contributeHelpers(context, depJRE)
+			if err = b.contributeHelpers(context, depJRE, cr); err != nil {
+				return libcnb.BuildResult{}, fmt.Errorf("unable to contribute helpers\n%w", err)
+			}
+			if err = b.contributeArchives(cr, context, depJRE); err != nil {
+				return libcnb.BuildResult{}, fmt.Errorf("unable to contribute class archives\n%w", err)
+			}
 		}
 	}
 
 	return b.Result, nil
 }
 
+// checkVendor ensures that the vendor requested in .sdkmanrc is the one provided by this buildpack.  If it is not,
+// the build fails unless $BP_JVM_VENDOR_FALLBACK allows another distribution to be used.
+func (b *Build) checkVendor(cr libpak.ConfigurationResolver, vendor string, info libcnb.BuildpackInfo, deps ...libpak.BuildpackDependency) error {
+	candidates := []string{info.ID, info.Name}
+	for _, d := range deps {
+		candidates = append(candidates, d.Name)
+	}
+
+	matched, known := MatchesVendor(vendor, candidates...)
+	if !known {
+		b.Logger.Body(color.New(color.Faint, color.Bold).Sprintf(
+			"WARNING: unknown Java vendor %q in .sdkmanrc, using %s", vendor, info.Name))
+		return nil
+	}
+
+	if matched {
+		f := color.New(color.Faint)
+		b.Logger.Body(f.Sprintf("Using Java vendor %s extracted from .sdkmanrc", vendor))
+		return nil
+	}
+
+	if !cr.ResolveBool("BP_JVM_VENDOR_FALLBACK") {
+		return fmt.Errorf("unable to build, Java vendor %q from .sdkmanrc is not provided by %s, set $BP_JVM_VENDOR_FALLBACK to use it anyway or use a buildpack that provides %s\n",
+			vendor, info.Name, strings.Join(SDKMANVendors[vendor], " or "))
+	}
+
+	b.Logger.Body(color.New(color.Faint, color.Bold).Sprintf(
+		"WARNING: Java vendor %q from .sdkmanrc is not provided by %s, using it instead", vendor, info.Name))
+	return nil
+}
+
 func (b *Build) contributeJDK(jdkDep libpak.BuildpackDependency) error {
 	jdk, be, err := NewJDK(jdkDep, b.DependencyCache, b.CertLoader)
 	if err != nil {
@@ -254,16 +326,116 @@ func (b *Build) contributeJLink(configurationResolver libpak.ConfigurationResolv
 		return fmt.Errorf("unable to parse jlink arguments %s %w\n", args, err)
 	}
 
+	var profile JLinkProfile
+	if s, ok := configurationResolver.Resolve("BP_JVM_JLINK_PROFILE"); ok {
+		if profile, err = ParseJLinkProfile(s); err != nil {
+			return fmt.Errorf("unable to parse $BP_JVM_JLINK_PROFILE\n%w", err)
+		}
+
+		// the profile replaces the default arguments, but not those that are explicitly configured
+		if !explicit {
+			argList = []string{}
+		}
+	}
+
 	jlink, err := NewJLink(appPath, effect.NewExecutor(), argList, b.CertLoader, planEntryMetadata, explicit)
 	if err != nil {
 		return fmt.Errorf("unable to create jlink jre\n%w", err)
 	}
 	jlink.JavaVersion = jdkDep.Version
+	jlink.JDKDigest = jdkDep.SHA256
+	jlink.Profile = profile
+	if s, ok := configurationResolver.Resolve("BP_JVM_JLINK_EXTRA_MODULES"); ok {
+		jlink.ExtraModules = strings.FieldsFunc(s, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	}
 	jlink.Logger = b.Logger
 	b.Result.Layers = append(b.Result.Layers, jlink)
 	return nil
 }
 
+// contributeArchives contributes the CDS archive or AOT cache, created by a training run of the application with the
+// launch JVM, if either is enabled.
+func (b *Build) contributeArchives(cr libpak.ConfigurationResolver, context libcnb.BuildContext, jvmDep libpak.BuildpackDependency) error {
+	cds := cr.ResolveBool("BP_JVM_CDS_ENABLED")
+	aot := cr.ResolveBool("BP_JVM_AOT_CACHE_ENABLED")
+
+	if aot && IsBeforeJava24(jvmDep.Version) {
+		b.Logger.Body(color.New(color.Faint, color.Bold).Sprintf(
+			"WARNING: AOT cache requires Java 24+ but Java %s is being used, skipping AOT cache", jvmDep.Version))
+		aot = false
+	}
+
+	if !cds && !aot {
+		return nil
+	}
+
+	if cds && aot {
+		return fmt.Errorf("unable to build, $BP_JVM_CDS_ENABLED and $BP_JVM_AOT_CACHE_ENABLED cannot both be set\n")
+	}
+
+	if cds && IsBeforeJava13(jvmDep.Version) {
+		return fmt.Errorf("unable to build, CDS archive creation is compatible with Java 13+ only\n")
+	}
+
+	var jvmLayer string
+	for _, l := range b.Result.Layers {
+		switch l.(type) {
+		case JRE, JLink:
+			jvmLayer = l.Name()
+		}
+	}
+	if jvmLayer == "" {
+		return fmt.Errorf("unable to find JVM layer for training run")
+	}
+
+	key := "BP_JVM_CDS_TRAINING_ARGS"
+	if aot {
+		key = "BP_JVM_AOT_TRAINING_ARGS"
+	}
+
+	var trainingArgs []string
+	if s, ok := cr.Resolve(key); ok {
+		args, err := shellwords.Parse(s)
+		if err != nil {
+			return fmt.Errorf("unable to parse training arguments %s\n%w", s, err)
+		}
+		trainingArgs = args
+	} else {
+		args, err := DefaultTrainingArgs(context.Application.Path)
+		if err != nil {
+			return fmt.Errorf("unable to determine training arguments\n%w", err)
+		}
+		if args == nil {
+			return fmt.Errorf("unable to build, $%s must be set to train applications other than Spring Boot\n", key)
+		}
+		trainingArgs = args
+	}
+
+	timeout := DefaultTrainingRunTimeout
+	if s, ok := cr.Resolve("BP_JVM_TRAINING_RUN_TIMEOUT"); ok {
+		t, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("unable to parse $BP_JVM_TRAINING_RUN_TIMEOUT %s\n%w", s, err)
+		}
+		timeout = t
+	}
+	executor := TrainingRunExecutor{Timeout: timeout}
+
+	if aot {
+		a := NewAOTCache(context.Application.Path, executor, jvmLayer, jvmDep.SHA256, trainingArgs, context.Buildpack.Info)
+		a.Logger = b.Logger
+		b.Result.Layers = append(b.Result.Layers, a)
+		return nil
+	}
+
+	c := NewCDS(context.Application.Path, executor, jvmLayer, jvmDep.SHA256, trainingArgs, context.Buildpack.Info)
+	c.Logger = b.Logger
+	b.Result.Layers = append(b.Result.Layers, c)
+	return nil
+}
+
 func (b *Build) contributeNIK(jdkDep libpak.BuildpackDependency, nativeDep libpak.BuildpackDependency) error {
 	if !(len(b.Native.CustomCommand) > 0) {
 		return fmt.Errorf("unable to create NIK, custom command has not been supplied by buildpack")
@@ -278,7 +450,7 @@ func (b *Build) contributeNIK(jdkDep libpak.BuildpackDependency, nativeDep libpa
 	return nil
 }
 
-func (b *Build) contributeHelpers(context libcnb.BuildContext, depJRE libpak.BuildpackDependency) {
+func (b *Build) contributeHelpers(context libcnb.BuildContext, depJRE libpak.BuildpackDependency, cr libpak.ConfigurationResolver) error {
 	helpers := []string{"java-opts", "jvm-heap", "link-local-dns", "memory-calculator",
 		"security-providers-configurer", "jmx", "jfr", "openssl-certificate-loader"}
 
@@ -295,6 +467,14 @@ func (b *Build) contributeHelpers(context libcnb.BuildContext, depJRE libpak.Bui
 		helpers = append(helpers, "active-processor-count")
 	}
 
+	if cr.ResolveBool("BP_JVM_CDS_ENABLED") {
+		helpers = append(helpers, "cds")
+	}
+
+	if cr.ResolveBool("BP_JVM_AOT_CACHE_ENABLED") && !IsBeforeJava24(depJRE.Version) {
+		helpers = append(helpers, "aot-cache")
+	}
+
 	found := false
 	for _, custom := range b.CustomHelpers {
 		if found {
@@ -319,6 +499,19 @@ func (b *Build) contributeHelpers(context libcnb.BuildContext, depJRE libpak.Bui
 	jsp := NewJavaSecurityProperties(context.Buildpack.Info)
 	jsp.Logger = b.Logger
 	b.Result.Layers = append(b.Result.Layers, jsp)
+
+	// an application built from source is replaced once compiled, so its classes are counted at launch instead
+	source, err := IsSourceApplication(context.Application.Path)
+	if err != nil {
+		return fmt.Errorf("unable to determine if application is built from source\n%w", err)
+	}
+	if !source {
+		acc := NewApplicationClassCount(context.Application.Path, context.Buildpack.Info)
+		acc.Logger = b.Logger
+		b.Result.Layers = append(b.Result.Layers, acc)
+	}
+
+	return nil
 }
 
 func (b Build) warnIfJreNotUsed(jreMissing, jreSkipped bool) {
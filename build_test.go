@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/paketo-buildpacks/libpak/bard"
 
@@ -103,10 +104,11 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
 		Expect(err).NotTo(HaveOccurred())
 
-		Expect(result.Layers).To(HaveLen(3))
+		Expect(result.Layers).To(HaveLen(4))
 		Expect(result.Layers[0].Name()).To(Equal("jre"))
 		Expect(result.Layers[1].Name()).To(Equal("helper"))
 		Expect(result.Layers[2].Name()).To(Equal("java-security-properties"))
+		Expect(result.Layers[3].Name()).To(Equal("application-class-count"))
 
 		Expect(result.BOM.Entries).To(HaveLen(2))
 		Expect(result.BOM.Entries[0].Name).To(Equal("jre"))
@@ -115,6 +117,29 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		Expect(result.BOM.Entries[1].Launch).To(BeTrue())
 	})
 
+	it("does not count classes of applications built from source", func() {
+		ctx.Application.Path = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "pom.xml"), []byte("<project/>"), 0644)).To(Succeed())
+
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"version": "17.0.0",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(3))
+		Expect(result.Layers[2].Name()).To(Equal("java-security-properties"))
+	})
+
 	it("contributes available next JRE version when Manifest refers to not available version", func() {
 		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
 		ctx.Buildpack.API = "0.6"
@@ -147,10 +172,11 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
 		Expect(err).NotTo(HaveOccurred())
 
-		Expect(result.Layers).To(HaveLen(3))
+		Expect(result.Layers).To(HaveLen(4))
 		Expect(result.Layers[0].Name()).To(Equal("jre"))
 		Expect(result.Layers[1].Name()).To(Equal("helper"))
 		Expect(result.Layers[2].Name()).To(Equal("java-security-properties"))
+		Expect(result.Layers[3].Name()).To(Equal("application-class-count"))
 
 		Expect(result.BOM.Entries).To(HaveLen(2))
 		Expect(result.BOM.Entries[0].Name).To(Equal("jre"))
@@ -196,6 +222,111 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		Expect(err.Error()).To(ContainSubstring("no valid dependencies for jre, 20, and test-stack-id in [(jre, 8.0.432, [test-stack-id]) (jre, 23.0.1, [test-stack-id]) (jre, 43.43.43, [test-stack-id])]"))
 	})
 
+	it("selects the patch version requested via sdkmanrc if $BP_JVM_VERSION_PATCH_POLICY is pinned", func() {
+		t.Setenv("BP_JVM_VERSION_PATCH_POLICY", "pinned")
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.API = "0.6"
+
+		ctx.Application.Path = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, ".sdkmanrc"), []byte(`java=17.0.6-librca`), 0644)).To(Succeed())
+
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"name":    "BellSoft Liberica JRE",
+					"version": "17.0.6",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+				{
+					"id":      "jre",
+					"name":    "BellSoft Liberica JRE",
+					"version": "17.0.9",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Layers[0].(libjvm.JRE).LayerContributor.Dependency.Version).To(Equal("17.0.6"))
+
+		t.Setenv("BP_JVM_VERSION_PATCH_POLICY", "latest")
+
+		result, err = libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Layers[0].(libjvm.JRE).LayerContributor.Dependency.Version).To(Equal("17.0.9"))
+	})
+
+	it("selects the JRE of the vendor requested via sdkmanrc", func() {
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.API = "0.6"
+
+		ctx.Application.Path = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, ".sdkmanrc"), []byte(`java=17.0.8-graal`), 0644)).To(Succeed())
+
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"name":    "BellSoft Liberica JRE",
+					"version": "17.0.9",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+				{
+					"id":      "jre",
+					"name":    "GraalVM JRE",
+					"version": "17.0.8",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers[0].(libjvm.JRE).LayerContributor.Dependency.Name).To(Equal("GraalVM JRE"))
+	})
+
+	context("vendor requested via sdkmanrc is not available", func() {
+		it.Before(func() {
+			ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+			ctx.Buildpack.API = "0.6"
+			ctx.Buildpack.Info.Name = "BellSoft Liberica Buildpack"
+
+			ctx.Application.Path = t.TempDir()
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, ".sdkmanrc"), []byte(`java=17.0.8-zulu`), 0644)).To(Succeed())
+
+			ctx.Buildpack.Metadata = map[string]interface{}{
+				"dependencies": []map[string]interface{}{
+					{
+						"id":      "jre",
+						"name":    "BellSoft Liberica JRE",
+						"version": "17.0.9",
+						"stacks":  []interface{}{"test-stack-id"},
+					},
+				},
+			}
+			ctx.StackID = "test-stack-id"
+		})
+
+		it("provides meaningful error message", func() {
+			_, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring(`Java vendor "zulu" from .sdkmanrc is not provided by BellSoft Liberica Buildpack`)))
+		})
+
+		it("falls back to the provided JRE if $BP_JVM_VENDOR_FALLBACK", func() {
+			t.Setenv("BP_JVM_VENDOR_FALLBACK", "true")
+
+			result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[0].(libjvm.JRE).LayerContributor.Dependency.Name).To(Equal("BellSoft Liberica JRE"))
+		})
+	})
+
 	it("provides meaningful error message if user requested via env.var a non available JRE", func() {
 		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
 		ctx.Buildpack.API = "0.6"
@@ -229,6 +360,64 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		Expect(err.Error()).To(ContainSubstring("no valid dependencies for jre, 24, and test-stack-id in [(jre, 8.0.432, [test-stack-id]) (jre, 23.0.1, [test-stack-id]) (jre, 43.43.43, [test-stack-id])]"))
 	})
 
+	context("$BP_JVM_VERSION_FALLBACK", func() {
+		it.Before(func() {
+			ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+			ctx.Buildpack.API = "0.6"
+			ctx.Buildpack.Metadata = map[string]interface{}{
+				"dependencies": []map[string]interface{}{
+					{
+						"id":      "jre",
+						"version": "8.0.432",
+						"stacks":  []interface{}{"test-stack-id"},
+					},
+					{
+						"id":      "jre",
+						"version": "23.0.1",
+						"stacks":  []interface{}{"test-stack-id"},
+					},
+					{
+						"id":      "jre",
+						"version": "25.0.1",
+						"stacks":  []interface{}{"test-stack-id"},
+					},
+				},
+			}
+			ctx.StackID = "test-stack-id"
+			t.Setenv("BP_JVM_VERSION", "20")
+		})
+
+		it("contributes the next available JRE", func() {
+			t.Setenv("BP_JVM_VERSION_FALLBACK", "next-available")
+
+			result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.BOM.Entries[0].Metadata["version"]).To(Equal("23.0.1"))
+		})
+
+		it("contributes the next LTS JRE", func() {
+			t.Setenv("BP_JVM_VERSION_FALLBACK", "next-lts")
+
+			result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.BOM.Entries[0].Metadata["version"]).To(Equal("25.0.1"))
+		})
+
+		it("fails with none", func() {
+			t.Setenv("BP_JVM_VERSION_FALLBACK", "none")
+
+			_, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("unable to find dependency for JRE 20")))
+		})
+
+		it("fails with an unknown fallback", func() {
+			t.Setenv("BP_JVM_VERSION_FALLBACK", "latest")
+
+			_, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("unable to parse $BP_JVM_VERSION_FALLBACK")))
+		})
+	})
+
 	it("contributes security-providers-classpath-8 before Java 9", func() {
 		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
 		ctx.Buildpack.Metadata = map[string]interface{}{
@@ -260,6 +449,192 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		}))
 	})
 
+	it("does not allow jlink before Java 9", func() {
+		t.Setenv("BP_JVM_VERSION", "8.0.*")
+		t.Setenv("BP_JVM_JLINK_ENABLED", "true")
+
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jdk",
+					"version": "8.0.432",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		_, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).To(MatchError(ContainSubstring("jlink is compatible with Java 9+ only")))
+	})
+
+	it("contributes CDS archive and helper if $BP_JVM_CDS_ENABLED", func() {
+		t.Setenv("BP_JVM_CDS_ENABLED", "true")
+		t.Setenv("BP_JVM_CDS_TRAINING_ARGS", "-cp /workspace test.Main")
+
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"version": "17.0.0",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(5))
+		Expect(result.Layers[1].(libpak.HelperLayerContributor).Names).To(ContainElement("cds"))
+		Expect(result.Layers[4].Name()).To(Equal("cds"))
+		Expect(result.Layers[4].(libjvm.CDS).JVMLayer).To(Equal("jre"))
+		Expect(result.Layers[4].(libjvm.CDS).TrainingArgs).To(Equal([]string{"-cp", "/workspace", "test.Main"}))
+
+		types := result.Layers[0].(libjvm.JRE).LayerContributor.ExpectedTypes
+		Expect(types.Build).To(BeTrue())
+		Expect(types.Cache).To(BeTrue())
+		Expect(types.Launch).To(BeTrue())
+		Expect(LaunchContribution).NotTo(HaveKey("cache"))
+	})
+
+	it("stops training runs after $BP_JVM_TRAINING_RUN_TIMEOUT", func() {
+		t.Setenv("BP_JVM_CDS_ENABLED", "true")
+		t.Setenv("BP_JVM_CDS_TRAINING_ARGS", "-cp /workspace test.Main")
+		t.Setenv("BP_JVM_TRAINING_RUN_TIMEOUT", "10m")
+
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"version": "17.0.0",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers[4].(libjvm.CDS).Executor).To(Equal(libjvm.TrainingRunExecutor{Timeout: 10 * time.Minute}))
+	})
+
+	it("requires CDS training arguments for applications other than Spring Boot", func() {
+		t.Setenv("BP_JVM_CDS_ENABLED", "true")
+
+		ctx.Application.Path = t.TempDir()
+		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "META-INF"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"),
+			[]byte("Main-Class: test.Main\n"), 0644)).To(Succeed())
+
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"version": "17.0.0",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		_, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).To(MatchError(ContainSubstring("$BP_JVM_CDS_TRAINING_ARGS must be set to train applications other than Spring Boot")))
+	})
+
+	it("does not contribute CDS archive before Java 13", func() {
+		t.Setenv("BP_JVM_CDS_ENABLED", "true")
+
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"version": "11.0.0",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		_, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).To(MatchError(ContainSubstring("CDS archive creation is compatible with Java 13+ only")))
+	})
+
+	it("contributes AOT cache and helper if $BP_JVM_AOT_CACHE_ENABLED", func() {
+		t.Setenv("BP_JVM_AOT_CACHE_ENABLED", "true")
+		t.Setenv("BP_JVM_AOT_TRAINING_ARGS", "-cp /workspace test.Main")
+
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"version": "24.0.0",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(5))
+		Expect(result.Layers[1].(libpak.HelperLayerContributor).Names).To(ContainElement("aot-cache"))
+		Expect(result.Layers[4].Name()).To(Equal("aot-cache"))
+		Expect(result.Layers[4].(libjvm.AOTCache).TrainingArgs).To(Equal([]string{"-cp", "/workspace", "test.Main"}))
+	})
+
+	it("does not contribute AOT cache before Java 24", func() {
+		t.Setenv("BP_JVM_AOT_CACHE_ENABLED", "true")
+		t.Setenv("BP_JVM_AOT_TRAINING_ARGS", "-cp /workspace test.Main")
+
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"version": "21.0.0",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		result, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(4))
+		Expect(result.Layers[1].(libpak.HelperLayerContributor).Names).NotTo(ContainElement("aot-cache"))
+	})
+
+	it("does not allow both CDS archive and AOT cache", func() {
+		t.Setenv("BP_JVM_AOT_CACHE_ENABLED", "true")
+		t.Setenv("BP_JVM_CDS_ENABLED", "true")
+
+		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
+		ctx.Buildpack.Metadata = map[string]interface{}{
+			"dependencies": []map[string]interface{}{
+				{
+					"id":      "jre",
+					"version": "24.0.0",
+					"stacks":  []interface{}{"test-stack-id"},
+				},
+			},
+		}
+		ctx.StackID = "test-stack-id"
+
+		_, err := libjvm.NewBuild(bard.NewLogger(io.Discard)).Build(ctx)
+		Expect(err).To(MatchError(ContainSubstring("cannot both be set")))
+	})
+
 	it("contributes security-providers-classpath-9 after Java 9", func() {
 		ctx.Plan.Entries = append(ctx.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "jre", Metadata: LaunchContribution})
 		ctx.Buildpack.Metadata = map[string]interface{}{
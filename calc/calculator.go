@@ -18,6 +18,7 @@ package calc
 
 import (
 	"fmt"
+	"strconv"
 )
 
 const (
@@ -26,10 +27,19 @@ const (
 )
 
 type Calculator struct {
-	HeadRoom         int
+	HeadRoom int
+
+	// HeapPercentage is the percentage of total memory to use for the heap. If zero, the heap is whatever memory
+	// remains after all other regions have been allocated.
+	HeapPercentage float64
+
 	LoadedClassCount int
-	ThreadCount      int
-	TotalMemory      Size
+
+	// Profile is applied on top of the calculated memory regions.
+	Profile Profile
+
+	ThreadCount int
+	TotalMemory Size
 }
 
 func (c Calculator) Calculate(flags string) (MemoryRegions, error) {
@@ -38,11 +48,20 @@ func (c Calculator) Calculate(flags string) (MemoryRegions, error) {
 		return MemoryRegions{}, fmt.Errorf("unable to create memory regions from flags\n%w", err)
 	}
 
+	if c.Profile.DirectMemory != nil && m.DirectMemory.Provenance != UserConfigured {
+		m.DirectMemory = *c.Profile.DirectMemory
+	}
+
 	if m.Metaspace == nil {
 		m.Metaspace = &Metaspace{
 			Value:      ClassOverhead + (ClassSize * int64(c.LoadedClassCount)),
 			Provenance: Calculated,
 		}
+
+		// the JVM shrinks the compressed class space to fit metaspace, so metaspace must hold the configured size
+		if m.CompressedClassSpace != nil && m.CompressedClassSpace.Value > m.Metaspace.Value {
+			m.Metaspace.Value = m.CompressedClassSpace.Value
+		}
 	}
 
 	f, err := m.FixedRegionsSize(c.ThreadCount)
@@ -74,11 +93,45 @@ func (c Calculator) Calculate(flags string) (MemoryRegions, error) {
 		)
 	}
 
+	// -Xmx takes precedence over -XX:MaxRAMPercentage, which takes precedence over a configured percentage
+	p := m.MaxRAMPercentage
+	if p == nil && c.HeapPercentage > 0 {
+		p = &RAMPercentage{Name: MaxRAMPercentage, Value: c.HeapPercentage, Provenance: Calculated}
+	}
+	if m.Heap == nil && p != nil {
+		h := Heap(p.Of(c.TotalMemory))
+		m.Heap = &h
+	} else {
+		p = nil
+	}
+
+	// the garbage collector's native memory scales with the heap, so a calculated heap shares the remaining memory
+	// with it rather than taking all of it.  It is only modelled for a selected collector, as the JVM chooses its
+	// default collector from the memory and CPUs available.
+	r := m.GarbageCollector.OverheadRatio()
 	if m.Heap == nil {
+		a := c.TotalMemory.Value - n.Value
+		h := int64(float64(a) / (1 + r))
+
 		m.Heap = &Heap{
-			Value:      c.TotalMemory.Value - n.Value,
+			Value:      h,
 			Provenance: Calculated,
 		}
+		if m.GarbageCollector != "" {
+			m.GarbageCollectorOverhead = &GarbageCollectorOverhead{
+				Value:      a - h,
+				Provenance: Calculated,
+			}
+		}
+	} else if m.GarbageCollector != "" {
+		m.GarbageCollectorOverhead = &GarbageCollectorOverhead{
+			Value:      int64(float64(m.Heap.Value) * r),
+			Provenance: Calculated,
+		}
+	}
+
+	if c.Profile.InitialHeapEqualsMax && m.InitialHeap == nil && m.InitialRAMPercentage == nil {
+		m.InitialHeap = &InitialHeap{Value: m.Heap.Value, Provenance: Calculated}
 	}
 
 	a, err := m.AllRegionsSize(c.ThreadCount)
@@ -86,7 +139,17 @@ func (c Calculator) Calculate(flags string) (MemoryRegions, error) {
 		return MemoryRegions{}, fmt.Errorf("unable to calculate all regions size\n%w", err)
 	}
 
-	if a.Value > c.TotalMemory.Value {
+	if a.Value > c.TotalMemory.Value && p != nil {
+		return MemoryRegions{}, fmt.Errorf(
+			"heap of %s%% of %s (%s) does not leave enough memory for non-heap regions, all memory regions require %s: %s",
+			strconv.FormatFloat(p.Value, 'f', -1, 64), c.TotalMemory, Size(*m.Heap), a, m.AllRegionsString(c.ThreadCount))
+	} else if a.Value > c.TotalMemory.Value && m.GarbageCollectorOverhead != nil &&
+		a.Value-m.GarbageCollectorOverhead.Value <= c.TotalMemory.Value {
+		// the overhead is an estimate, so it alone does not reject a heap the user has sized
+		m.Warnings = append(m.Warnings, fmt.Sprintf(
+			"estimated %s GC overhead of %s means all memory regions may require %s which is greater than %s available for allocation: %s",
+			m.GarbageCollector, m.GarbageCollectorOverhead, a, c.TotalMemory, m.AllRegionsString(c.ThreadCount)))
+	} else if a.Value > c.TotalMemory.Value {
 		return MemoryRegions{}, fmt.Errorf(
 			"all memory regions require %s which is greater than %s available for allocation: %s",
 			a, c.TotalMemory, m.AllRegionsString(c.ThreadCount))
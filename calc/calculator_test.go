@@ -44,6 +44,34 @@ func testCalculator(t *testing.T, context spec.G, it spec.S) {
 		Expect(m.Metaspace).To(Equal(&calc.Metaspace{Value: 14_580_000, Provenance: calc.Calculated}))
 	})
 
+	it("calculates metaspace large enough for compressed class space", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("-XX:CompressedClassSpaceSize=64M")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.Metaspace).To(Equal(&calc.Metaspace{Value: 64 * calc.Mebi, Provenance: calc.Calculated}))
+	})
+
+	it("includes garbage collector thread stacks in fixed regions", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("-XX:ParallelGCThreads=4 -XX:ConcGCThreads=2")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.Heap.Value).To(Equal(calc.Gibi - 10*calc.Mebi - 14_580_000 - 240*calc.Mebi - 8*calc.Mebi))
+	})
+
 	it("returns error if fixed regions are too large", func() {
 		c := calc.Calculator{
 			HeadRoom:         0,
@@ -99,6 +127,181 @@ func testCalculator(t *testing.T, context spec.G, it spec.S) {
 		Expect(m.Heap).To(Equal(&calc.Heap{Value: 794920672, Provenance: calc.Calculated}))
 	})
 
+	it("does not calculate garbage collector overhead if no garbage collector is selected", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("-Xmx100M")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.GarbageCollectorOverhead).To(BeNil())
+	})
+
+	it("calculates heap with garbage collector overhead", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("-XX:+UseZGC")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.Heap).To(Equal(&calc.Heap{Value: 691235366, Provenance: calc.Calculated}))
+		Expect(m.GarbageCollectorOverhead).To(Equal(&calc.GarbageCollectorOverhead{Value: 103685306, Provenance: calc.Calculated}))
+	})
+
+	it("calculates garbage collector overhead for user configured heap", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("-XX:+UseG1GC -Xmx100M")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.GarbageCollectorOverhead).To(Equal(&calc.GarbageCollectorOverhead{Value: 10 * calc.Mebi, Provenance: calc.Calculated}))
+	})
+
+	it("returns error if garbage collector overhead does not fit", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		_, err := c.Calculate("-XX:+UseZGC -Xmx760M")
+		Expect(err).To(MatchError(HavePrefix("all memory regions require")))
+		Expect(err).To(MatchError(ContainSubstring("114M -XX:+UseZGC GC overhead")))
+	})
+
+	it("warns if only garbage collector overhead does not fit user configured heap", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("-XX:+UseZGC -Xmx700M")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.Heap).To(Equal(&calc.Heap{Value: 700 * calc.Mebi, Provenance: calc.UserConfigured}))
+		Expect(m.Warnings).To(ConsistOf(HavePrefix("estimated -XX:+UseZGC GC overhead of 105M means all memory regions may require")))
+	})
+
+	it("calculates heap from -XX:MaxRAMPercentage", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("-XX:MaxRAMPercentage=50 -XX:InitialRAMPercentage=25")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.Heap).To(Equal(&calc.Heap{Value: 512 * calc.Mebi, Provenance: calc.UserConfigured}))
+		Expect(m.InitialRAMPercentage).To(Equal(&calc.RAMPercentage{Name: calc.InitialRAMPercentage, Value: 25, Provenance: calc.UserConfigured}))
+	})
+
+	it("calculates heap from heap percentage", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			HeapPercentage:   50,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.Heap).To(Equal(&calc.Heap{Value: 512 * calc.Mebi, Provenance: calc.Calculated}))
+	})
+
+	it("prefers -Xmx over RAM percentages", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			HeapPercentage:   50,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("-XX:MaxRAMPercentage=75 -Xmx100M")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.Heap).To(Equal(&calc.Heap{Value: 100 * calc.Mebi, Provenance: calc.UserConfigured}))
+	})
+
+	it("returns error if heap percentage does not leave room for non-heap regions", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			HeapPercentage:   90,
+			LoadedClassCount: 100,
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		_, err := c.Calculate("")
+		Expect(err).To(MatchError(HavePrefix("heap of 90% of 1G (943718K) does not leave enough memory for non-heap regions")))
+	})
+
+	it("sets initial heap to heap for profile", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			Profile:          calc.Profiles["throughput"],
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.InitialHeap).To(Equal(&calc.InitialHeap{Value: 794920672, Provenance: calc.Calculated}))
+	})
+
+	it("does not change user configured initial heap for profile", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			Profile:          calc.Profiles["throughput"],
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("-Xms100M")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.InitialHeap).To(Equal(&calc.InitialHeap{Value: 100 * calc.Mebi, Provenance: calc.UserConfigured}))
+	})
+
+	it("sets direct memory for profile", func() {
+		c := calc.Calculator{
+			HeadRoom:         0,
+			LoadedClassCount: 100,
+			Profile:          calc.Profiles["batch"],
+			ThreadCount:      2,
+			TotalMemory:      calc.Size{Value: calc.Gibi},
+		}
+
+		m, err := c.Calculate("")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.DirectMemory).To(Equal(calc.DirectMemory{Value: 4 * calc.Mebi, Provenance: calc.Calculated}))
+		Expect(m.Heap).To(Equal(&calc.Heap{Value: 794920672 + (6 * calc.Mebi), Provenance: calc.Calculated}))
+	})
+
 	it("returns error of all regions are too large", func() {
 		c := calc.Calculator{
 			HeadRoom:         0,
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CodeHeap is a segment of the segmented code cache. The segments together make up the reserved code cache.
+type CodeHeap struct {
+	Size
+
+	Name string
+}
+
+var CodeHeapRE = regexp.MustCompile(fmt.Sprintf("^-XX:(NonNMethod|Profiled|NonProfiled)CodeHeapSize=(%s)$", SizePattern))
+
+const (
+	NonNMethodCodeHeap  = "NonNMethod"
+	ProfiledCodeHeap    = "Profiled"
+	NonProfiledCodeHeap = "NonProfiled"
+)
+
+func (c CodeHeap) String() string {
+	return fmt.Sprintf("-XX:%sCodeHeapSize=%s", c.Name, c.Size)
+}
+
+func MatchCodeHeap(s string) bool {
+	return CodeHeapRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseCodeHeap(s string) (CodeHeap, error) {
+	g := CodeHeapRE.FindStringSubmatch(s)
+	if g == nil {
+		return CodeHeap{}, fmt.Errorf("%s does not match code heap pattern %s", s, CodeHeapRE.String())
+	}
+
+	z, err := ParseSize(g[2])
+	if err != nil {
+		return CodeHeap{}, fmt.Errorf("unable to parse code heap size\n%w", err)
+	}
+
+	return CodeHeap{Size: z, Name: g[1]}, nil
+}
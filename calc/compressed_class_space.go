@@ -0,0 +1,52 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var CompressedClassSpaceRE = regexp.MustCompile(fmt.Sprintf("^-XX:CompressedClassSpaceSize=(%s)$", SizePattern))
+
+// CompressedClassSpace is the part of metaspace that holds class metadata when compressed class pointers are in use.
+// Its committed memory is limited by -XX:MaxMetaspaceSize, so it is not counted in addition to metaspace, but a
+// calculated metaspace is made large enough to hold it.
+type CompressedClassSpace Size
+
+func (c CompressedClassSpace) String() string {
+	return fmt.Sprintf("-XX:CompressedClassSpaceSize=%s", Size(c))
+}
+
+func MatchCompressedClassSpace(s string) bool {
+	return CompressedClassSpaceRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseCompressedClassSpace(s string) (*CompressedClassSpace, error) {
+	g := CompressedClassSpaceRE.FindStringSubmatch(s)
+	if g == nil {
+		return nil, fmt.Errorf("%s does not match compressed class space pattern %s", s, CompressedClassSpaceRE.String())
+	}
+
+	z, err := ParseSize(g[1])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse compressed class space size\n%w", err)
+	}
+
+	c := CompressedClassSpace(z)
+	return &c, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GarbageCollector is a JVM garbage collector selected with a -XX:+Use<Name>GC flag.
+type GarbageCollector string
+
+const (
+	SerialGC        GarbageCollector = "Serial"
+	ParallelGC      GarbageCollector = "Parallel"
+	ConcMarkSweepGC GarbageCollector = "ConcMarkSweep"
+	G1GC            GarbageCollector = "G1"
+	ShenandoahGC    GarbageCollector = "Shenandoah"
+	ZGC             GarbageCollector = "Z"
+	EpsilonGC       GarbageCollector = "Epsilon"
+)
+
+var GarbageCollectorRE = regexp.MustCompile("^-XX:\\+Use(Serial|Parallel|ParallelOld|ConcMarkSweep|G1|Shenandoah|Z|Epsilon)GC$")
+
+// GarbageCollectorOverheadRatios are estimates of the native memory used by each collector (remembered sets, card
+// tables, marking bitmaps, forwarding data and page tables) as a fraction of the heap size.
+var GarbageCollectorOverheadRatios = map[GarbageCollector]float64{
+	SerialGC:        0.02,
+	ParallelGC:      0.05,
+	ConcMarkSweepGC: 0.05,
+	G1GC:            0.10,
+	ShenandoahGC:    0.12,
+	ZGC:             0.15,
+	EpsilonGC:       0,
+}
+
+func (g GarbageCollector) String() string {
+	return fmt.Sprintf("-XX:+Use%sGC", string(g))
+}
+
+// OverheadRatio returns the native memory overhead of the collector as a fraction of the heap size. An unknown or
+// unselected collector has no modelled overhead.
+func (g GarbageCollector) OverheadRatio() float64 {
+	return GarbageCollectorOverheadRatios[g]
+}
+
+func MatchGarbageCollector(s string) bool {
+	return GarbageCollectorRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseGarbageCollector(s string) (GarbageCollector, error) {
+	g := GarbageCollectorRE.FindStringSubmatch(strings.TrimSpace(s))
+	if g == nil {
+		return "", fmt.Errorf("%s does not match garbage collector pattern %s", s, GarbageCollectorRE.String())
+	}
+
+	if g[1] == "ParallelOld" {
+		return ParallelGC, nil
+	}
+
+	return GarbageCollector(g[1]), nil
+}
+
+// GarbageCollectorOverhead is the native memory used by the garbage collector outside of the heap.
+type GarbageCollectorOverhead Size
+
+func (g GarbageCollectorOverhead) String() string {
+	return Size(g).String()
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/calc"
+)
+
+func testGarbageCollector(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("formats", func() {
+		Expect(calc.ZGC.String()).To(Equal("-XX:+UseZGC"))
+	})
+
+	it("matches -XX:+Use*GC", func() {
+		Expect(calc.MatchGarbageCollector("-XX:+UseG1GC")).To(BeTrue())
+		Expect(calc.MatchGarbageCollector("-XX:+UseZGC")).To(BeTrue())
+	})
+
+	it("does not match non -XX:+Use*GC", func() {
+		Expect(calc.MatchGarbageCollector("-XX:-UseG1GC")).To(BeFalse())
+		Expect(calc.MatchGarbageCollector("-XX:+UseStringDeduplication")).To(BeFalse())
+	})
+
+	it("parses", func() {
+		Expect(calc.ParseGarbageCollector("-XX:+UseShenandoahGC")).To(Equal(calc.ShenandoahGC))
+	})
+
+	it("parses ParallelOld as Parallel", func() {
+		Expect(calc.ParseGarbageCollector("-XX:+UseParallelOldGC")).To(Equal(calc.ParallelGC))
+	})
+
+	it("returns overhead ratio", func() {
+		Expect(calc.G1GC.OverheadRatio()).To(Equal(0.10))
+		Expect(calc.GarbageCollector("").OverheadRatio()).To(BeZero())
+	})
+
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// GarbageCollectorThreadStack is the stack reserved for each garbage collector thread. These are VM threads, sized
+	// by -XX:VMThreadStackSize rather than -Xss.
+	GarbageCollectorThreadStack = Size{Value: 1 * Mebi, Provenance: Default}
+	GarbageCollectorThreadsRE   = regexp.MustCompile("^-XX:(Parallel|Conc)GCThreads=([\\d]+)$")
+)
+
+const (
+	ParallelGarbageCollectorThreads   = "Parallel"
+	ConcurrentGarbageCollectorThreads = "Conc"
+)
+
+// GarbageCollectorThreads is a configured number of parallel or concurrent garbage collector threads, each of which
+// reserves a stack in addition to the application's threads.
+type GarbageCollectorThreads struct {
+	Name       string
+	Value      int
+	Provenance Provenance
+}
+
+func (g GarbageCollectorThreads) String() string {
+	return fmt.Sprintf("-XX:%sGCThreads=%d", g.Name, g.Value)
+}
+
+// Size returns the size of the stacks reserved by the threads.
+func (g GarbageCollectorThreads) Size() Size {
+	return Size{Value: GarbageCollectorThreadStack.Value * int64(g.Value), Provenance: g.Provenance}
+}
+
+func MatchGarbageCollectorThreads(s string) bool {
+	return GarbageCollectorThreadsRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseGarbageCollectorThreads(s string) (GarbageCollectorThreads, error) {
+	g := GarbageCollectorThreadsRE.FindStringSubmatch(s)
+	if g == nil {
+		return GarbageCollectorThreads{}, fmt.Errorf("%s does not match garbage collector threads pattern %s",
+			s, GarbageCollectorThreadsRE.String())
+	}
+
+	v, err := strconv.Atoi(g[2])
+	if err != nil {
+		return GarbageCollectorThreads{}, fmt.Errorf("unable to parse garbage collector threads\n%w", err)
+	}
+
+	return GarbageCollectorThreads{Name: g[1], Value: v}, nil
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/calc"
+)
+
+func testGarbageCollectorThreads(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("formats", func() {
+		Expect(calc.GarbageCollectorThreads{Name: calc.ParallelGarbageCollectorThreads, Value: 4}.String()).
+			To(Equal("-XX:ParallelGCThreads=4"))
+	})
+
+	it("sizes thread stacks", func() {
+		Expect(calc.GarbageCollectorThreads{Name: calc.ConcurrentGarbageCollectorThreads, Value: 2, Provenance: calc.UserConfigured}.Size()).
+			To(Equal(calc.Size{Value: 2 * calc.Mebi, Provenance: calc.UserConfigured}))
+	})
+
+	it("matches -XX:ParallelGCThreads and -XX:ConcGCThreads", func() {
+		Expect(calc.MatchGarbageCollectorThreads("-XX:ParallelGCThreads=4")).To(BeTrue())
+		Expect(calc.MatchGarbageCollectorThreads("-XX:ConcGCThreads=2")).To(BeTrue())
+	})
+
+	it("does not match other thread flags", func() {
+		Expect(calc.MatchGarbageCollectorThreads("-XX:CICompilerCount=2")).To(BeFalse())
+	})
+
+	it("parses", func() {
+		Expect(calc.ParseGarbageCollectorThreads("-XX:ConcGCThreads=2")).To(Equal(calc.GarbageCollectorThreads{
+			Name: calc.ConcurrentGarbageCollectorThreads, Value: 2,
+		}))
+	})
+}
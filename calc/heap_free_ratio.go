@@ -0,0 +1,58 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var HeapFreeRatioRE = regexp.MustCompile("^-XX:MaxHeapFreeRatio=([\\d]+)$")
+
+// HeapFreeRatio is the maximum percentage of the heap that is kept free after a collection before the heap is shrunk.
+// It changes how much of the heap is committed, but not how much is reserved.
+type HeapFreeRatio struct {
+	Value      int
+	Provenance Provenance
+}
+
+func (h HeapFreeRatio) String() string {
+	return fmt.Sprintf("-XX:MaxHeapFreeRatio=%d", h.Value)
+}
+
+func MatchHeapFreeRatio(s string) bool {
+	return HeapFreeRatioRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseHeapFreeRatio(s string) (*HeapFreeRatio, error) {
+	g := HeapFreeRatioRE.FindStringSubmatch(s)
+	if g == nil {
+		return nil, fmt.Errorf("%s does not match heap free ratio pattern %s", s, HeapFreeRatioRE.String())
+	}
+
+	v, err := strconv.Atoi(g[1])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse heap free ratio\n%w", err)
+	}
+
+	if v > 100 {
+		return nil, fmt.Errorf("heap free ratio %d must not be greater than 100", v)
+	}
+
+	return &HeapFreeRatio{Value: v}, nil
+}
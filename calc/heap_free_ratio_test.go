@@ -0,0 +1,53 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/calc"
+)
+
+func testHeapFreeRatio(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("formats", func() {
+		Expect(calc.HeapFreeRatio{Value: 40}.String()).To(Equal("-XX:MaxHeapFreeRatio=40"))
+	})
+
+	it("matches -XX:MaxHeapFreeRatio", func() {
+		Expect(calc.MatchHeapFreeRatio("-XX:MaxHeapFreeRatio=40")).To(BeTrue())
+	})
+
+	it("does not match non -XX:MaxHeapFreeRatio", func() {
+		Expect(calc.MatchHeapFreeRatio("-XX:MinHeapFreeRatio=40")).To(BeFalse())
+	})
+
+	it("parses", func() {
+		Expect(calc.ParseHeapFreeRatio("-XX:MaxHeapFreeRatio=40")).To(Equal(&calc.HeapFreeRatio{Value: 40}))
+	})
+
+	it("returns error if greater than 100", func() {
+		_, err := calc.ParseHeapFreeRatio("-XX:MaxHeapFreeRatio=101")
+		Expect(err).To(MatchError("heap free ratio 101 must not be greater than 100"))
+	})
+
+}
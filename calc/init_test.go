@@ -26,11 +26,19 @@ import (
 func TestUnit(t *testing.T) {
 	suite := spec.New("libjvm/calc", spec.Report(report.Terminal{}))
 	suite("Calculator", testCalculator)
+	suite("CodeHeap", testCodeHeap)
+	suite("CompressedClassSpace", testCompressedClassSpace)
 	suite("DirectMemory", testDirectMemory)
+	suite("GarbageCollector", testGarbageCollector)
+	suite("GarbageCollectorThreads", testGarbageCollectorThreads)
 	suite("Headroom", testHeadroom)
 	suite("Heap", testHeap)
+	suite("HeapFreeRatio", testHeapFreeRatio)
 	suite("Metaspace", testMetaspace)
+	suite("InitialHeap", testInitialHeap)
 	suite("MemoryRegions", testMemoryRegions)
+	suite("Profile", testProfile)
+	suite("RAMPercentage", testRAMPercentage)
 	suite("ReservedCodeCache", testReservedCodeCache)
 	suite("Size", testSize)
 	suite("Stack", testStack)
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var InitialHeapRE = regexp.MustCompile(fmt.Sprintf("^(?:-Xms|-XX:InitialHeapSize=)(%s)$", SizePattern))
+
+// InitialHeap is the size of the heap committed at startup. It is part of the heap and is not counted in addition to it.
+type InitialHeap Size
+
+func (i InitialHeap) String() string {
+	return fmt.Sprintf("-Xms%s", Size(i))
+}
+
+func MatchInitialHeap(s string) bool {
+	return InitialHeapRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseInitialHeap(s string) (*InitialHeap, error) {
+	g := InitialHeapRE.FindStringSubmatch(s)
+	if g == nil {
+		return nil, fmt.Errorf("%s does not match initial heap pattern %s", s, InitialHeapRE.String())
+	}
+
+	z, err := ParseSize(g[1])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse initial heap size\n%w", err)
+	}
+
+	i := InitialHeap(z)
+	return &i, nil
+}
@@ -24,12 +24,31 @@ import (
 )
 
 type MemoryRegions struct {
-	DirectMemory      DirectMemory
-	HeadRoom          *HeadRoom
-	Heap              *Heap
-	Metaspace         *Metaspace
-	ReservedCodeCache ReservedCodeCache
-	Stack             Stack
+	CodeHeaps                []CodeHeap
+	CompressedClassSpace     *CompressedClassSpace
+	DirectMemory             DirectMemory
+	GarbageCollector         GarbageCollector
+	GarbageCollectorOverhead *GarbageCollectorOverhead
+	GarbageCollectorThreads  []GarbageCollectorThreads
+	HeadRoom                 *HeadRoom
+	Heap                     *Heap
+	HeapFreeRatio            *HeapFreeRatio
+	InitialHeap              *InitialHeap
+	InitialRAMPercentage     *RAMPercentage
+	MaxRAMPercentage         *RAMPercentage
+	Metaspace                *Metaspace
+	ReservedCodeCache        ReservedCodeCache
+	Stack                    Stack
+
+	// Warnings describe problems with the calculation that do not prevent the JVM from starting.
+	Warnings []string
+}
+
+// Region describes a single memory region, the JVM flag that configures it and the size of the region.
+type Region struct {
+	Name string
+	Flag string
+	Size Size
 }
 
 func NewMemoryRegionsFromFlags(flags string) (MemoryRegions, error) {
@@ -51,6 +70,41 @@ func NewMemoryRegionsFromFlags(flags string) (MemoryRegions, error) {
 				return MemoryRegions{}, fmt.Errorf("unable to parse direct memory\n%w", err)
 			}
 			m.DirectMemory.Provenance = UserConfigured
+		} else if MatchGarbageCollector(f) {
+			m.GarbageCollector, err = ParseGarbageCollector(f)
+			if err != nil {
+				return MemoryRegions{}, fmt.Errorf("unable to parse garbage collector\n%w", err)
+			}
+		} else if MatchGarbageCollectorThreads(f) {
+			g, err := ParseGarbageCollectorThreads(f)
+			if err != nil {
+				return MemoryRegions{}, fmt.Errorf("unable to parse garbage collector threads\n%w", err)
+			}
+			g.Provenance = UserConfigured
+			m.setGarbageCollectorThreads(g)
+		} else if MatchHeapFreeRatio(f) {
+			m.HeapFreeRatio, err = ParseHeapFreeRatio(f)
+			if err != nil {
+				return MemoryRegions{}, fmt.Errorf("unable to parse heap free ratio\n%w", err)
+			}
+			m.HeapFreeRatio.Provenance = UserConfigured
+		} else if MatchInitialHeap(f) {
+			m.InitialHeap, err = ParseInitialHeap(f)
+			if err != nil {
+				return MemoryRegions{}, fmt.Errorf("unable to parse initial heap\n%w", err)
+			}
+			m.InitialHeap.Provenance = UserConfigured
+		} else if MatchRAMPercentage(f) {
+			r, err := ParseRAMPercentage(f)
+			if err != nil {
+				return MemoryRegions{}, fmt.Errorf("unable to parse RAM percentage\n%w", err)
+			}
+			r.Provenance = UserConfigured
+			if r.Name == MaxRAMPercentage {
+				m.MaxRAMPercentage = r
+			} else {
+				m.InitialRAMPercentage = r
+			}
 		} else if MatchHeap(f) {
 			m.Heap, err = ParseHeap(f)
 			if err != nil {
@@ -63,6 +117,19 @@ func NewMemoryRegionsFromFlags(flags string) (MemoryRegions, error) {
 				return MemoryRegions{}, fmt.Errorf("unable to parse metaspace\n%w", err)
 			}
 			m.Metaspace.Provenance = UserConfigured
+		} else if MatchCompressedClassSpace(f) {
+			m.CompressedClassSpace, err = ParseCompressedClassSpace(f)
+			if err != nil {
+				return MemoryRegions{}, fmt.Errorf("unable to parse compressed class space\n%w", err)
+			}
+			m.CompressedClassSpace.Provenance = UserConfigured
+		} else if MatchCodeHeap(f) {
+			c, err := ParseCodeHeap(f)
+			if err != nil {
+				return MemoryRegions{}, fmt.Errorf("unable to parse code heap\n%w", err)
+			}
+			c.Provenance = UserConfigured
+			m.setCodeHeap(c)
 		} else if MatchReservedCodeCache(f) {
 			m.ReservedCodeCache, err = ParseReservedCodeCache(f)
 			if err != nil {
@@ -78,9 +145,64 @@ func NewMemoryRegionsFromFlags(flags string) (MemoryRegions, error) {
 		}
 	}
 
+	if err := m.reconcileCodeHeaps(); err != nil {
+		return MemoryRegions{}, err
+	}
+
 	return m, nil
 }
 
+// reconcileCodeHeaps ensures that the reserved code cache is large enough to hold the configured code heap segments.
+// If all segments are configured, the JVM sizes the reserved code cache as their sum.
+func (m *MemoryRegions) reconcileCodeHeaps() error {
+	if len(m.CodeHeaps) == 0 {
+		return nil
+	}
+
+	var sum int64
+	for _, c := range m.CodeHeaps {
+		sum += c.Value
+	}
+	all := len(m.CodeHeaps) == 3
+
+	if m.ReservedCodeCache.Provenance == UserConfigured {
+		if (all && sum != m.ReservedCodeCache.Value) || sum > m.ReservedCodeCache.Value {
+			return fmt.Errorf("code heap segments require %s which does not fit %s",
+				Size{Value: sum}, m.ReservedCodeCache)
+		}
+		return nil
+	}
+
+	if all || sum > m.ReservedCodeCache.Value {
+		m.ReservedCodeCache = ReservedCodeCache{Value: sum, Provenance: Calculated}
+	}
+
+	return nil
+}
+
+// setCodeHeap adds a code heap segment, replacing an earlier segment with the same name as the JVM does.
+func (m *MemoryRegions) setCodeHeap(c CodeHeap) {
+	for i, e := range m.CodeHeaps {
+		if e.Name == c.Name {
+			m.CodeHeaps[i] = c
+			return
+		}
+	}
+	m.CodeHeaps = append(m.CodeHeaps, c)
+}
+
+// setGarbageCollectorThreads adds a garbage collector thread count, replacing an earlier count with the same name as
+// the JVM does.
+func (m *MemoryRegions) setGarbageCollectorThreads(g GarbageCollectorThreads) {
+	for i, e := range m.GarbageCollectorThreads {
+		if e.Name == g.Name {
+			m.GarbageCollectorThreads[i] = g
+			return
+		}
+	}
+	m.GarbageCollectorThreads = append(m.GarbageCollectorThreads, g)
+}
+
 func (m MemoryRegions) AllRegionsSize(threadCount int) (Size, error) {
 	if m.HeadRoom == nil {
 		return Size{}, fmt.Errorf("unable to calculate all regions size without heap")
@@ -113,8 +235,13 @@ func (m MemoryRegions) FixedRegionsSize(threadCount int) (Size, error) {
 		return Size{}, fmt.Errorf("unable to calculate fixed regions size without metaspace")
 	}
 
+	v := m.DirectMemory.Value + m.Metaspace.Value + m.ReservedCodeCache.Value + (m.Stack.Value * int64(threadCount))
+	for _, g := range m.GarbageCollectorThreads {
+		v += g.Size().Value
+	}
+
 	return Size{
-		Value:      m.DirectMemory.Value + m.Metaspace.Value + m.ReservedCodeCache.Value + (m.Stack.Value * int64(threadCount)),
+		Value:      v,
 		Provenance: Calculated,
 	}, nil
 }
@@ -128,6 +255,9 @@ func (m MemoryRegions) FixedRegionsString(threadCount int) string {
 	}
 	s = append(s, m.ReservedCodeCache.String())
 	s = append(s, fmt.Sprintf("%s * %d threads", m.Stack.String(), threadCount))
+	for _, g := range m.GarbageCollectorThreads {
+		s = append(s, fmt.Sprintf("%s * %s", GarbageCollectorThreadStack, g.String()))
+	}
 
 	return strings.Join(s, ", ")
 }
@@ -142,8 +272,13 @@ func (m MemoryRegions) NonHeapRegionsSize(threadCount int) (Size, error) {
 		return Size{}, fmt.Errorf("unable to calculate fixed regions size\n%w", err)
 	}
 
+	v := s.Value + m.HeadRoom.Value
+	if m.GarbageCollectorOverhead != nil {
+		v += m.GarbageCollectorOverhead.Value
+	}
+
 	return Size{
-		Value:      s.Value + m.HeadRoom.Value,
+		Value:      v,
 		Provenance: Calculated,
 	}, nil
 }
@@ -154,7 +289,59 @@ func (m MemoryRegions) NonHeapRegionsString(threadCount int) string {
 	if m.HeadRoom != nil {
 		s = append(s, fmt.Sprintf("%s headroom", m.HeadRoom.String()))
 	}
+	if m.GarbageCollectorOverhead != nil {
+		s = append(s, fmt.Sprintf("%s %s GC overhead", m.GarbageCollectorOverhead.String(), m.GarbageCollector))
+	}
 	s = append(s, m.FixedRegionsString(threadCount))
 
 	return strings.Join(s, ", ")
 }
+
+// Regions returns every region that has been set, in the order that flags are contributed to the JVM. The stack region
+// is reported per-thread.
+func (m MemoryRegions) Regions() []Region {
+	var r []Region
+
+	r = append(r, Region{Name: "direct-memory", Flag: m.DirectMemory.String(), Size: Size(m.DirectMemory)})
+	if m.Heap != nil {
+		r = append(r, Region{Name: "heap", Flag: m.Heap.String(), Size: Size(*m.Heap)})
+	}
+	if m.InitialHeap != nil {
+		r = append(r, Region{Name: "initial-heap", Flag: m.InitialHeap.String(), Size: Size(*m.InitialHeap)})
+	}
+	if m.Metaspace != nil {
+		r = append(r, Region{Name: "metaspace", Flag: m.Metaspace.String(), Size: Size(*m.Metaspace)})
+	}
+	if m.CompressedClassSpace != nil {
+		r = append(r, Region{Name: "compressed-class-space", Flag: m.CompressedClassSpace.String(), Size: Size(*m.CompressedClassSpace)})
+	}
+	r = append(r, Region{Name: "reserved-code-cache", Flag: m.ReservedCodeCache.String(), Size: Size(m.ReservedCodeCache)})
+	for _, c := range m.CodeHeaps {
+		r = append(r, Region{Name: fmt.Sprintf("%s-code-heap", strings.ToLower(c.Name)), Flag: c.String(), Size: c.Size})
+	}
+	r = append(r, Region{Name: "stack", Flag: m.Stack.String(), Size: Size(m.Stack)})
+	for _, g := range m.GarbageCollectorThreads {
+		r = append(r, Region{Name: fmt.Sprintf("%s-gc-threads", strings.ToLower(g.Name)), Flag: g.String(), Size: g.Size()})
+	}
+	if m.GarbageCollectorOverhead != nil {
+		r = append(r, Region{Name: "garbage-collector", Size: Size(*m.GarbageCollectorOverhead)})
+	}
+	if m.HeadRoom != nil {
+		r = append(r, Region{Name: "head-room", Size: Size(*m.HeadRoom)})
+	}
+
+	return r
+}
+
+// Flags returns the flags of the regions that have been calculated, leaving out those configured by the user, which
+// the JVM already receives.
+func (m MemoryRegions) Flags() []string {
+	var flags []string
+	for _, r := range m.Regions() {
+		if r.Flag != "" && r.Size.Provenance != UserConfigured {
+			flags = append(flags, r.Flag)
+		}
+	}
+
+	return flags
+}
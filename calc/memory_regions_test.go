@@ -54,6 +54,134 @@ func testMemoryRegions(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("code heaps", func() {
+		it("sizes reserved code cache from all segments", func() {
+			m, err := calc.NewMemoryRegionsFromFlags("-XX:NonNMethodCodeHeapSize=8M -XX:ProfiledCodeHeapSize=100M -XX:NonProfiledCodeHeapSize=100M")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.ReservedCodeCache).To(Equal(calc.ReservedCodeCache{Value: 208 * calc.Mebi, Provenance: calc.Calculated}))
+			Expect(m.CodeHeaps).To(HaveLen(3))
+		})
+
+		it("grows reserved code cache to fit segments", func() {
+			m, err := calc.NewMemoryRegionsFromFlags("-XX:ProfiledCodeHeapSize=300M")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.ReservedCodeCache).To(Equal(calc.ReservedCodeCache{Value: 300 * calc.Mebi, Provenance: calc.Calculated}))
+		})
+
+		it("keeps default reserved code cache if segments fit", func() {
+			m, err := calc.NewMemoryRegionsFromFlags("-XX:ProfiledCodeHeapSize=100M")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.ReservedCodeCache).To(Equal(calc.DefaultReservedCodeCache))
+		})
+
+		it("replaces repeated segments", func() {
+			m, err := calc.NewMemoryRegionsFromFlags("-XX:ProfiledCodeHeapSize=300M -XX:ProfiledCodeHeapSize=100M")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.CodeHeaps).To(Equal([]calc.CodeHeap{
+				{Size: calc.Size{Value: 100 * calc.Mebi, Provenance: calc.UserConfigured}, Name: calc.ProfiledCodeHeap},
+			}))
+		})
+
+		it("returns error if segments do not fit user configured reserved code cache", func() {
+			_, err := calc.NewMemoryRegionsFromFlags("-XX:ReservedCodeCacheSize=100M -XX:ProfiledCodeHeapSize=200M")
+			Expect(err).To(MatchError("code heap segments require 200M which does not fit -XX:ReservedCodeCacheSize=100M"))
+		})
+	})
+
+	context("other flags", func() {
+		it("parses compressed class space, heap free ratio and thread stack size", func() {
+			Expect(calc.NewMemoryRegionsFromFlags("-XX:CompressedClassSpaceSize=64M -XX:MaxHeapFreeRatio=40 -XX:ThreadStackSize=512")).To(Equal(calc.MemoryRegions{
+				CompressedClassSpace: &calc.CompressedClassSpace{Value: 64 * calc.Mebi, Provenance: calc.UserConfigured},
+				DirectMemory:         calc.DefaultDirectMemory,
+				HeapFreeRatio:        &calc.HeapFreeRatio{Value: 40, Provenance: calc.UserConfigured},
+				ReservedCodeCache:    calc.DefaultReservedCodeCache,
+				Stack:                calc.Stack{Value: 512 * calc.Kibi, Provenance: calc.UserConfigured},
+			}))
+		})
+
+		it("parses garbage collector threads, replacing repeated counts", func() {
+			m, err := calc.NewMemoryRegionsFromFlags("-XX:ParallelGCThreads=8 -XX:ConcGCThreads=2 -XX:ParallelGCThreads=4")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.GarbageCollectorThreads).To(Equal([]calc.GarbageCollectorThreads{
+				{Name: calc.ParallelGarbageCollectorThreads, Value: 4, Provenance: calc.UserConfigured},
+				{Name: calc.ConcurrentGarbageCollectorThreads, Value: 2, Provenance: calc.UserConfigured},
+			}))
+		})
+
+		it("includes garbage collector thread stacks in fixed regions", func() {
+			m = calc.MemoryRegions{
+				DirectMemory:            calc.DirectMemory{Value: calc.Kibi},
+				GarbageCollectorThreads: []calc.GarbageCollectorThreads{{Name: calc.ParallelGarbageCollectorThreads, Value: 2}},
+				Metaspace:               &calc.Metaspace{Value: calc.Kibi},
+				ReservedCodeCache:       calc.ReservedCodeCache{Value: calc.Kibi},
+				Stack:                   calc.Stack{Value: calc.Kibi},
+			}
+
+			Expect(m.FixedRegionsSize(2)).To(Equal(calc.Size{Value: 2*calc.Mebi + 5*calc.Kibi, Provenance: calc.Calculated}))
+			Expect(m.FixedRegionsString(2)).To(Equal(
+				"-XX:MaxDirectMemorySize=1K, -XX:MaxMetaspaceSize=1K, -XX:ReservedCodeCacheSize=1K, -Xss1K * 2 threads, 1M * -XX:ParallelGCThreads=2"))
+		})
+	})
+
+	context("garbage collector", func() {
+		it("parses garbage collector", func() {
+			Expect(calc.NewMemoryRegionsFromFlags("-XX:+UseZGC")).To(Equal(calc.MemoryRegions{
+				DirectMemory:      calc.DefaultDirectMemory,
+				GarbageCollector:  calc.ZGC,
+				ReservedCodeCache: calc.DefaultReservedCodeCache,
+				Stack:             calc.DefaultStack,
+			}))
+		})
+
+		it("includes garbage collector overhead in non-heap regions", func() {
+			m = calc.MemoryRegions{
+				DirectMemory:             calc.DirectMemory{Value: calc.Kibi},
+				GarbageCollector:         calc.G1GC,
+				GarbageCollectorOverhead: &calc.GarbageCollectorOverhead{Value: calc.Kibi},
+				HeadRoom:                 &calc.HeadRoom{Value: calc.Kibi},
+				Metaspace:                &calc.Metaspace{Value: calc.Kibi},
+				ReservedCodeCache:        calc.ReservedCodeCache{Value: calc.Kibi},
+				Stack:                    calc.Stack{Value: calc.Kibi},
+			}
+
+			Expect(m.NonHeapRegionsSize(2)).To(Equal(calc.Size{Value: 7 * calc.Kibi, Provenance: calc.Calculated}))
+			Expect(m.NonHeapRegionsString(2)).To(Equal(
+				"1K headroom, 1K -XX:+UseG1GC GC overhead, -XX:MaxDirectMemorySize=1K, -XX:MaxMetaspaceSize=1K, -XX:ReservedCodeCacheSize=1K, -Xss1K * 2 threads"))
+		})
+	})
+
+	context("regions", func() {
+		it("returns set regions", func() {
+			m = calc.MemoryRegions{
+				DirectMemory:      calc.DirectMemory{Value: calc.Kibi, Provenance: calc.Default},
+				HeadRoom:          &calc.HeadRoom{Value: calc.Kibi, Provenance: calc.Calculated},
+				Heap:              &calc.Heap{Value: calc.Kibi, Provenance: calc.Calculated},
+				ReservedCodeCache: calc.ReservedCodeCache{Value: calc.Kibi, Provenance: calc.UserConfigured},
+				Stack:             calc.Stack{Value: calc.Kibi, Provenance: calc.Default},
+			}
+
+			Expect(m.Regions()).To(Equal([]calc.Region{
+				{Name: "direct-memory", Flag: "-XX:MaxDirectMemorySize=1K", Size: calc.Size{Value: calc.Kibi, Provenance: calc.Default}},
+				{Name: "heap", Flag: "-Xmx1K", Size: calc.Size{Value: calc.Kibi, Provenance: calc.Calculated}},
+				{Name: "reserved-code-cache", Flag: "-XX:ReservedCodeCacheSize=1K", Size: calc.Size{Value: calc.Kibi, Provenance: calc.UserConfigured}},
+				{Name: "stack", Flag: "-Xss1K", Size: calc.Size{Value: calc.Kibi, Provenance: calc.Default}},
+				{Name: "head-room", Size: calc.Size{Value: calc.Kibi, Provenance: calc.Calculated}},
+			}))
+		})
+
+		it("returns flags of regions not configured by the user", func() {
+			m = calc.MemoryRegions{
+				DirectMemory:      calc.DirectMemory{Value: calc.Kibi, Provenance: calc.Default},
+				HeadRoom:          &calc.HeadRoom{Value: calc.Kibi, Provenance: calc.Calculated},
+				Heap:              &calc.Heap{Value: calc.Kibi, Provenance: calc.Calculated},
+				ReservedCodeCache: calc.ReservedCodeCache{Value: calc.Kibi, Provenance: calc.UserConfigured},
+				Stack:             calc.Stack{Value: calc.Kibi, Provenance: calc.Default},
+			}
+
+			Expect(m.Flags()).To(Equal([]string{"-XX:MaxDirectMemorySize=1K", "-Xmx1K", "-Xss1K"}))
+		})
+	})
+
 	context("all regions", func() {
 		it.Before(func() {
 			m = calc.MemoryRegions{
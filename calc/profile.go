@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// Profile is a vetted set of adjustments applied on top of the calculated memory regions.
+type Profile struct {
+	Name string
+
+	// AlwaysPreTouch touches every page of the heap at startup so that page faults do not occur while running.
+	AlwaysPreTouch bool
+
+	// DirectMemory replaces the default direct memory size if it has not been user configured.
+	DirectMemory *DirectMemory
+
+	// InitialHeapEqualsMax sets the initial heap to the maximum heap so that the heap never resizes.
+	InitialHeapEqualsMax bool
+}
+
+var Profiles = map[string]Profile{
+	"throughput": {
+		Name:                 "throughput",
+		InitialHeapEqualsMax: true,
+	},
+	"latency": {
+		Name:                 "latency",
+		AlwaysPreTouch:       true,
+		InitialHeapEqualsMax: true,
+	},
+	"batch": {
+		Name:         "batch",
+		DirectMemory: &DirectMemory{Value: 4 * Mebi, Provenance: Calculated},
+	},
+}
+
+func ParseProfile(s string) (Profile, error) {
+	p, ok := Profiles[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		var names []string
+		for n := range Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		return Profile{}, fmt.Errorf("unknown memory profile %q, must be one of %s", s, strings.Join(names, ", "))
+	}
+
+	return p, nil
+}
+
+// Flags returns the flags of the profile that are not memory regions, skipping any that have been set in flags.
+func (p Profile) Flags(flags string) ([]string, error) {
+	if !p.AlwaysPreTouch {
+		return nil, nil
+	}
+
+	f, err := shellwords.Parse(flags)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse flags\n%w", err)
+	}
+
+	for _, s := range f {
+		if s == "-XX:+AlwaysPreTouch" || s == "-XX:-AlwaysPreTouch" {
+			return nil, nil
+		}
+	}
+
+	return []string{"-XX:+AlwaysPreTouch"}, nil
+}
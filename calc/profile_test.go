@@ -0,0 +1,53 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/calc"
+)
+
+func testProfile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses", func() {
+		Expect(calc.ParseProfile("Latency")).To(Equal(calc.Profiles["latency"]))
+	})
+
+	it("returns error for unknown profile", func() {
+		_, err := calc.ParseProfile("fast")
+		Expect(err).To(MatchError(`unknown memory profile "fast", must be one of batch, latency, throughput`))
+	})
+
+	it("returns pre-touch flag", func() {
+		Expect(calc.Profiles["latency"].Flags("")).To(Equal([]string{"-XX:+AlwaysPreTouch"}))
+	})
+
+	it("does not return pre-touch flag if user configured", func() {
+		Expect(calc.Profiles["latency"].Flags("-XX:-AlwaysPreTouch")).To(BeEmpty())
+	})
+
+	it("does not return flags for profiles without them", func() {
+		Expect(calc.Profiles["throughput"].Flags("")).To(BeEmpty())
+	})
+
+}
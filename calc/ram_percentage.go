@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var RAMPercentageRE = regexp.MustCompile("^-XX:(Max|Initial)RAMPercentage=([\\d]+(?:\\.[\\d]+)?)$")
+
+const (
+	MaxRAMPercentage     = "Max"
+	InitialRAMPercentage = "Initial"
+)
+
+// RAMPercentage is a heap size configured as a percentage of the memory available to the JVM.
+type RAMPercentage struct {
+	Name       string
+	Value      float64
+	Provenance Provenance
+}
+
+func (r RAMPercentage) String() string {
+	return fmt.Sprintf("-XX:%sRAMPercentage=%s", r.Name, strconv.FormatFloat(r.Value, 'f', -1, 64))
+}
+
+// Of returns the size that the percentage represents of total.
+func (r RAMPercentage) Of(total Size) Size {
+	return Size{Value: int64(float64(total.Value) * r.Value / 100), Provenance: r.Provenance}
+}
+
+func MatchRAMPercentage(s string) bool {
+	return RAMPercentageRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseRAMPercentage(s string) (*RAMPercentage, error) {
+	g := RAMPercentageRE.FindStringSubmatch(s)
+	if g == nil {
+		return nil, fmt.Errorf("%s does not match RAM percentage pattern %s", s, RAMPercentageRE.String())
+	}
+
+	v, err := strconv.ParseFloat(g[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse RAM percentage\n%w", err)
+	}
+
+	if v > 100 {
+		return nil, fmt.Errorf("RAM percentage %s must not be greater than 100", g[2])
+	}
+
+	return &RAMPercentage{Name: g[1], Value: v}, nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/calc"
+)
+
+func testRAMPercentage(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("formats", func() {
+		Expect(calc.RAMPercentage{Name: calc.MaxRAMPercentage, Value: 75}.String()).To(Equal("-XX:MaxRAMPercentage=75"))
+		Expect(calc.RAMPercentage{Name: calc.InitialRAMPercentage, Value: 12.5}.String()).To(Equal("-XX:InitialRAMPercentage=12.5"))
+	})
+
+	it("matches RAM percentages", func() {
+		Expect(calc.MatchRAMPercentage("-XX:MaxRAMPercentage=75")).To(BeTrue())
+		Expect(calc.MatchRAMPercentage("-XX:InitialRAMPercentage=12.5")).To(BeTrue())
+	})
+
+	it("does not match non RAM percentages", func() {
+		Expect(calc.MatchRAMPercentage("-XX:MinRAMPercentage=50")).To(BeFalse())
+	})
+
+	it("parses", func() {
+		Expect(calc.ParseRAMPercentage("-XX:MaxRAMPercentage=75.0")).To(Equal(&calc.RAMPercentage{Name: calc.MaxRAMPercentage, Value: 75}))
+	})
+
+	it("returns error if greater than 100", func() {
+		_, err := calc.ParseRAMPercentage("-XX:MaxRAMPercentage=101")
+		Expect(err).To(MatchError("RAM percentage 101 must not be greater than 100"))
+	})
+
+	it("calculates size", func() {
+		Expect(calc.RAMPercentage{Value: 50, Provenance: calc.UserConfigured}.Of(calc.Size{Value: calc.Gibi})).
+			To(Equal(calc.Size{Value: 512 * calc.Mebi, Provenance: calc.UserConfigured}))
+	})
+
+}
@@ -41,6 +41,40 @@ const (
 
 var SizeRE = regexp.MustCompile(fmt.Sprintf("^%s$", SizePattern))
 
+func (p Provenance) String() string {
+	switch p {
+	case Default:
+		return "default"
+	case UserConfigured:
+		return "user-configured"
+	case Calculated:
+		return "calculated"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText renders the provenance by name so that it is readable in JSON reports.
+func (p Provenance) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Provenance) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "default":
+		*p = Default
+	case "user-configured":
+		*p = UserConfigured
+	case "calculated":
+		*p = Calculated
+	case "unknown":
+		*p = Unknown
+	default:
+		return fmt.Errorf("unknown provenance %q", string(text))
+	}
+	return nil
+}
+
 type Size struct {
 	Value      int64
 	Provenance Provenance
@@ -56,6 +56,15 @@ func testSize(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("provenance", func() {
+		it("formats provenance", func() {
+			Expect(calc.Default.String()).To(Equal("default"))
+			Expect(calc.UserConfigured.String()).To(Equal("user-configured"))
+			Expect(calc.Calculated.String()).To(Equal("calculated"))
+			Expect(calc.Unknown.String()).To(Equal("unknown"))
+		})
+	})
+
 	context("parse", func() {
 		it("parses bytes", func() {
 			Expect(calc.ParseSize("1")).To(Equal(calc.Size{Value: 1}))
@@ -25,6 +25,9 @@ import (
 var (
 	DefaultStack = Stack{Value: 1 * Mebi, Provenance: Default}
 	StackRE      = regexp.MustCompile(fmt.Sprintf("^-Xss(%s)$", SizePattern))
+
+	// ThreadStackSizeRE matches -XX:ThreadStackSize, an alternative to -Xss whose value is always in kibibytes.
+	ThreadStackSizeRE = regexp.MustCompile("^-XX:ThreadStackSize=([\\d]+)$")
 )
 
 type Stack Size
@@ -34,10 +37,20 @@ func (s Stack) String() string {
 }
 
 func MatchStack(s string) bool {
-	return StackRE.MatchString(strings.TrimSpace(s))
+	t := strings.TrimSpace(s)
+	return StackRE.MatchString(t) || ThreadStackSizeRE.MatchString(t)
 }
 
 func ParseStack(s string) (Stack, error) {
+	if g := ThreadStackSizeRE.FindStringSubmatch(s); g != nil {
+		z, err := ParseSize(g[1] + "K")
+		if err != nil {
+			return Stack{}, fmt.Errorf("unable to parse thread stack size\n%w", err)
+		}
+
+		return Stack(z), nil
+	}
+
 	g := StackRE.FindStringSubmatch(s)
 	if g == nil {
 		return Stack{}, fmt.Errorf("%s does not match stack pattern %s", s, StackRE.String())
@@ -46,4 +46,12 @@ func testStack(t *testing.T, context spec.G, it spec.S) {
 		Expect(calc.ParseStack("-Xss1K")).To(Equal(calc.Stack{Value: calc.Kibi}))
 	})
 
+	it("matches -XX:ThreadStackSize", func() {
+		Expect(calc.MatchStack("-XX:ThreadStackSize=512")).To(BeTrue())
+	})
+
+	it("parses -XX:ThreadStackSize in kibibytes", func() {
+		Expect(calc.ParseStack("-XX:ThreadStackSize=512")).To(Equal(calc.Stack{Value: 512 * calc.Kibi}))
+	})
+
 }
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/effect"
+)
+
+// CDS creates an application Class Data Sharing archive by running the application once at build time with
+// -XX:ArchiveClassesAtExit.
+type CDS struct {
+	TrainingRun
+}
+
+func NewCDS(applicationPath string, exec effect.Executor, jvmLayer string, jdkDigest string, trainingArgs []string, info libcnb.BuildpackInfo) CDS {
+	return CDS{
+		TrainingRun: NewTrainingRun("Class Data Sharing Archive", applicationPath, exec, jvmLayer, jdkDigest, trainingArgs, info),
+	}
+}
+
+func (c CDS) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	return c.TrainingRun.Contribute(layer, func(layer libcnb.Layer, jvm string) (libcnb.Layer, error) {
+		archive := filepath.Join(layer.Path, "application.jsa")
+
+		c.Logger.Bodyf("Creating CDS archive with training run")
+		if err := c.Run(layer, fmt.Sprintf("-XX:ArchiveClassesAtExit=%s", archive)); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to run CDS training run\n%w", err)
+		}
+
+		if _, err := os.Stat(archive); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("training run did not create CDS archive %s\n%w", archive, err)
+		}
+
+		layer.LaunchEnvironment.Default("BPI_JVM_CDS_ARCHIVE", archive)
+		layer.LaunchEnvironment.Default("BPI_JVM_CDS_JVM_FINGERPRINT", jvm)
+
+		return layer, nil
+	})
+}
+
+func (c CDS) Name() string {
+	return "cds"
+}
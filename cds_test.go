@@ -0,0 +1,156 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/effect"
+	"github.com/paketo-buildpacks/libpak/effect/mocks"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testCDS(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		ctx  libcnb.BuildContext
+		exec *mocks.Executor
+	)
+
+	it.Before(func() {
+		ctx.Application.Path = t.TempDir()
+		ctx.Layers.Path = t.TempDir()
+		exec = &mocks.Executor{}
+
+		Expect(os.MkdirAll(filepath.Join(ctx.Layers.Path, "jre", "lib"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Layers.Path, "jre", "release"), []byte(`JAVA_VERSION="17.0.9"`), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Layers.Path, "jre", "lib", "modules"), []byte{1, 2, 3}, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "alpha.class"), []byte{}, 0644)).To(Succeed())
+
+		exec.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+			e := args.Get(0).(effect.Execution)
+			archive := strings.TrimPrefix(e.Args[0], "-XX:ArchiveClassesAtExit=")
+			Expect(os.WriteFile(archive, []byte{}, 0644)).To(Succeed())
+		}).Return(nil)
+	})
+
+	it("contributes CDS archive", func() {
+		c := libjvm.NewCDS(ctx.Application.Path, exec, "jre", "test-sha256", []string{"-cp", ctx.Application.Path, "test.Main"}, ctx.Buildpack.Info)
+		c.Logger = bard.NewLogger(io.Discard)
+
+		layer, err := ctx.Layers.Layer("cds")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = c.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		f, err := libjvm.JVMFingerprint(filepath.Join(ctx.Layers.Path, "jre"))
+		Expect(err).NotTo(HaveOccurred())
+
+		e := exec.Calls[0].Arguments[0].(effect.Execution)
+		Expect(e.Command).To(Equal(filepath.Join(ctx.Layers.Path, "jre", "bin", "java")))
+		Expect(e.Args).To(Equal([]string{
+			"-XX:ArchiveClassesAtExit=" + filepath.Join(layer.Path, "application.jsa"), "-cp", ctx.Application.Path, "test.Main",
+		}))
+		Expect(e.Dir).To(Equal(ctx.Application.Path))
+
+		Expect(layer.LayerTypes.Launch).To(BeTrue())
+		Expect(layer.Metadata).To(HaveKeyWithValue("jdk-digest", "test-sha256"))
+		Expect(layer.LaunchEnvironment["BPI_JVM_CDS_ARCHIVE.default"]).To(Equal(filepath.Join(layer.Path, "application.jsa")))
+		Expect(layer.LaunchEnvironment["BPI_JVM_CDS_JVM_FINGERPRINT.default"]).To(Equal(f))
+	})
+
+	it("reuses CDS archive unless the JDK changes", func() {
+		contribute := func(layer libcnb.Layer, digest string) libcnb.Layer {
+			c := libjvm.NewCDS(ctx.Application.Path, exec, "jre", digest, []string{"test.Main"}, ctx.Buildpack.Info)
+			c.Logger = bard.NewLogger(io.Discard)
+
+			layer, err := c.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+			return layer
+		}
+
+		layer, err := ctx.Layers.Layer("cds")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer = contribute(layer, "test-sha256")
+
+		// a launch-only JVM layer is not restored on rebuild
+		Expect(os.RemoveAll(filepath.Join(ctx.Layers.Path, "jre"))).To(Succeed())
+		layer = contribute(layer, "test-sha256")
+		Expect(exec.Calls).To(HaveLen(1))
+
+		Expect(os.MkdirAll(filepath.Join(ctx.Layers.Path, "jre"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Layers.Path, "jre", "release"), []byte(`JAVA_VERSION="17.0.10"`), 0644)).To(Succeed())
+		contribute(layer, "another-sha256")
+		Expect(exec.Calls).To(HaveLen(2))
+	})
+
+	it("rebuilds CDS archive when the JVM layer changes", func() {
+		jvmMetadata := func(modules string) {
+			Expect(os.WriteFile(filepath.Join(ctx.Layers.Path, "jre.toml"),
+				[]byte(fmt.Sprintf("launch = true\n\n[metadata]\n  jlink-modules = [%q]\n", modules)), 0644)).To(Succeed())
+		}
+
+		contribute := func(layer libcnb.Layer) libcnb.Layer {
+			c := libjvm.NewCDS(ctx.Application.Path, exec, "jre", "test-sha256", []string{"test.Main"}, ctx.Buildpack.Info)
+			c.Logger = bard.NewLogger(io.Discard)
+
+			layer, err := c.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+			return layer
+		}
+
+		layer, err := ctx.Layers.Layer("cds")
+		Expect(err).NotTo(HaveOccurred())
+
+		jvmMetadata("java.base")
+		layer = contribute(layer)
+		layer = contribute(layer)
+		Expect(exec.Calls).To(HaveLen(1))
+
+		jvmMetadata("java.base,java.sql")
+		contribute(layer)
+		Expect(exec.Calls).To(HaveLen(2))
+	})
+
+	it("fails if the training run does not create an archive", func() {
+		exec = &mocks.Executor{}
+		exec.On("Execute", mock.Anything).Return(nil)
+
+		c := libjvm.NewCDS(ctx.Application.Path, exec, "jre", "test-sha256", []string{"test.Main"}, ctx.Buildpack.Info)
+		c.Logger = bard.NewLogger(io.Discard)
+
+		layer, err := ctx.Layers.Layer("cds")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = c.Contribute(layer)
+		Expect(err).To(MatchError(ContainSubstring("training run did not create CDS archive")))
+	})
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+const (
+	// ClassMagic is the magic number at the start of every class file.
+	ClassMagic uint32 = 0xCAFEBABE
+
+	// MaxClassSamplesPerArchive is the number of class files in each JAR, and outside of JARs, whose version is read.
+	// The classes in a JAR are almost always compiled for the same release, so a sample is enough.
+	MaxClassSamplesPerArchive = 16
+)
+
+// ReadJavaVersionFromClasses returns the lowest Java version able to run every class file sampled from the
+// application, including classes in JARs and JARs nested one level down, or an empty string if the application has
+// no class files.  Multi-release classes and module descriptors are ignored, as they are not required by the base
+// release.  Nested JARs that are compressed and too large to decompress into memory are skipped.
+func ReadJavaVersionFromClasses(appPath string) (string, error) {
+	var (
+		major   uint16
+		samples int
+	)
+
+	if _, err := os.Stat(appPath); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	if err := filepath.Walk(appPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(appPath, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case strings.HasSuffix(path, ".class"):
+			if samples >= MaxClassSamplesPerArchive || !sampledClass(filepath.ToSlash(rel)) {
+				return nil
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return fmt.Errorf("unable to open %s\n%w", path, err)
+			}
+
+			m, ok := classMajorVersion(f)
+			f.Close()
+			if ok {
+				samples++
+				if m > major {
+					major = m
+				}
+			}
+		case strings.HasSuffix(path, ".jar"):
+			f, z, err := openJAR(path)
+			if err != nil {
+				return err
+			} else if z == nil {
+				return nil
+			}
+			defer f.Close()
+
+			m, err := archiveMajorVersion(f, z, true)
+			if err != nil {
+				return fmt.Errorf("unable to read class versions in %s\n%w", path, err)
+			}
+			if m > major {
+				major = m
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("unable to walk %s\n%w", appPath, err)
+	}
+
+	if major == 0 {
+		return "", nil
+	}
+
+	return JavaVersionForClassMajorVersion(major), nil
+}
+
+// JavaVersionForClassMajorVersion returns the Java version that introduced a class file major version.  Versions before
+// Java 8 are reported as Java 8, the oldest version available.
+func JavaVersionForClassMajorVersion(major uint16) string {
+	if major <= 52 {
+		return "8"
+	}
+	return strconv.Itoa(int(major) - 44)
+}
+
+func sampledClass(name string) bool {
+	return !strings.HasPrefix(name, "META-INF/versions/") && filepath.Base(name) != "module-info.class"
+}
+
+// openJAR opens the JAR at path, returning a nil archive if it is not a JAR.
+func openJAR(path string) (*os.File, *zip.Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open %s\n%w", path, err)
+	}
+
+	i, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("unable to stat %s\n%w", path, err)
+	}
+
+	z, err := zip.NewReader(f, i.Size())
+	if errors.Is(err, zip.ErrFormat) {
+		f.Close()
+		return nil, nil, nil
+	} else if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("unable to open %s\n%w", path, err)
+	}
+
+	return f, z, nil
+}
+
+// archiveMajorVersion returns the highest major version of the classes sampled from an archive read from r, and if
+// nested is set, from the archives nested within it.
+func archiveMajorVersion(r io.ReaderAt, z *zip.Reader, nested bool) (uint16, error) {
+	var major uint16
+	samples := 0
+
+	for _, f := range z.File {
+		switch {
+		case strings.HasSuffix(f.Name, ".class") && samples < MaxClassSamplesPerArchive && sampledClass(f.Name):
+			c, err := f.Open()
+			if err != nil {
+				return 0, fmt.Errorf("unable to open %s\n%w", f.Name, err)
+			}
+
+			m, ok := classMajorVersion(c)
+			c.Close()
+			if ok {
+				samples++
+				if m > major {
+					major = m
+				}
+			}
+		case strings.HasSuffix(f.Name, ".jar") && nested:
+			nr, nz, err := count.OpenNestedJar(r, f)
+			if errors.Is(err, count.ErrNestedJarTooLarge) || (err == nil && nz == nil) {
+				continue
+			} else if err != nil {
+				return 0, fmt.Errorf("unable to open %s\n%w", f.Name, err)
+			}
+
+			m, err := archiveMajorVersion(nr, nz, false)
+			if err != nil {
+				return 0, fmt.Errorf("unable to read class versions in %s\n%w", f.Name, err)
+			}
+			if m > major {
+				major = m
+			}
+		}
+	}
+
+	return major, nil
+}
+
+// classMajorVersion reads the major version from a class file header, returning false if it is not a class file.
+func classMajorVersion(r io.Reader) (uint16, bool) {
+	var h struct {
+		Magic uint32
+		Minor uint16
+		Major uint16
+	}
+
+	if err := binary.Read(r, binary.BigEndian, &h); err != nil || h.Magic != ClassMagic {
+		return 0, false
+	}
+
+	return h.Major, true
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testClassVersion(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		appPath = t.TempDir()
+	})
+
+	class := func(major byte) []byte {
+		return []byte{0xCA, 0xFE, 0xBA, 0xBE, 0x00, 0x00, 0x00, major}
+	}
+
+	archive := func(method uint16, files map[string][]byte) []byte {
+		b := &bytes.Buffer{}
+		z := zip.NewWriter(b)
+		for n, c := range files {
+			w, err := z.CreateHeader(&zip.FileHeader{Name: n, Method: method})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write(c)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(z.Close()).To(Succeed())
+		return b.Bytes()
+	}
+
+	jar := func(files map[string][]byte) []byte {
+		return archive(zip.Deflate, files)
+	}
+
+	write := func(file string, content []byte) {
+		Expect(os.MkdirAll(filepath.Dir(filepath.Join(appPath, file)), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(appPath, file), content, 0644)).To(Succeed())
+	}
+
+	it("returns nothing without classes", func() {
+		Expect(libjvm.ReadJavaVersionFromClasses(appPath)).To(BeEmpty())
+	})
+
+	it("reads version from class files", func() {
+		write("BOOT-INF/classes/test/Alpha.class", class(55))
+		write("BOOT-INF/classes/test/Bravo.class", class(61))
+
+		Expect(libjvm.ReadJavaVersionFromClasses(appPath)).To(Equal("17"))
+	})
+
+	it("reads version from JARs and nested JARs", func() {
+		write("BOOT-INF/classes/test/Alpha.class", class(52))
+		write("BOOT-INF/lib/alpha.jar", jar(map[string][]byte{"test/Bravo.class": class(55)}))
+		write("application.jar", jar(map[string][]byte{
+			"BOOT-INF/lib/charlie.jar": jar(map[string][]byte{"test/Charlie.class": class(65)}),
+		}))
+
+		Expect(libjvm.ReadJavaVersionFromClasses(appPath)).To(Equal("21"))
+	})
+
+	it("reads version from stored nested JARs", func() {
+		write("application.jar", archive(zip.Store, map[string][]byte{
+			"BOOT-INF/lib/alpha.jar": archive(zip.Store, map[string][]byte{"test/Alpha.class": class(61)}),
+		}))
+
+		Expect(libjvm.ReadJavaVersionFromClasses(appPath)).To(Equal("17"))
+	})
+
+	it("samples class files outside of JARs", func() {
+		for i := 0; i < libjvm.MaxClassSamplesPerArchive; i++ {
+			write(fmt.Sprintf("test/Alpha%02d.class", i), class(52))
+		}
+		write("test/Bravo.class", class(61))
+
+		Expect(libjvm.ReadJavaVersionFromClasses(appPath)).To(Equal("8"))
+	})
+
+	it("ignores multi-release classes and module descriptors", func() {
+		write("test/Alpha.class", class(52))
+		write("module-info.class", class(53))
+		write("alpha.jar", jar(map[string][]byte{
+			"test/Bravo.class":                      class(52),
+			"META-INF/versions/17/test/Bravo.class": class(61),
+		}))
+
+		Expect(libjvm.ReadJavaVersionFromClasses(appPath)).To(Equal("8"))
+	})
+
+	it("ignores files that are not classes", func() {
+		write("test/Alpha.class", []byte("not a class"))
+		write("alpha.jar", []byte("not a jar"))
+
+		Expect(libjvm.ReadJavaVersionFromClasses(appPath)).To(BeEmpty())
+	})
+
+	it("maps class file versions to Java versions", func() {
+		Expect(libjvm.JavaVersionForClassMajorVersion(50)).To(Equal("8"))
+		Expect(libjvm.JavaVersionForClassMajorVersion(52)).To(Equal("8"))
+		Expect(libjvm.JavaVersionForClassMajorVersion(55)).To(Equal("11"))
+		Expect(libjvm.JavaVersionForClassMajorVersion(69)).To(Equal("25"))
+	})
+}
@@ -38,7 +38,9 @@ func main() {
 			cl = libjvm.NewCertificateLoader()
 
 			a  = helper.ActiveProcessorCount{Logger: l}
+			ac = helper.AOTCache{Logger: l}
 			c  = helper.SecurityProvidersConfigurer{Logger: l}
+			cd = helper.CDS{Logger: l}
 			d  = helper.LinkLocalDNS{Logger: l}
 			j  = helper.JavaOpts{Logger: l}
 			jh = helper.JVMHeapDump{Logger: l}
@@ -47,6 +49,8 @@ func main() {
 				MemoryLimitPathV1: helper.DefaultMemoryLimitPathV1,
 				MemoryLimitPathV2: helper.DefaultMemoryLimitPathV2,
 				MemoryInfoPath:    helper.DefaultMemoryInfoPath,
+				CgroupPath:        helper.DefaultCgroupPath,
+				MountInfoPath:     helper.DefaultMountInfoPath,
 			}
 			o  = helper.OpenSSLCertificateLoader{CertificateLoader: cl, Logger: l}
 			s8 = helper.SecurityProvidersClasspath8{Logger: l}
@@ -66,6 +70,8 @@ func main() {
 
 		return sherpa.Helpers(map[string]sherpa.ExecD{
 			"active-processor-count":         a,
+			"aot-cache":                      ac,
+			"cds":                            cd,
 			"java-opts":                      j,
 			"jvm-heap":                       jh,
 			"link-local-dns":                 d,
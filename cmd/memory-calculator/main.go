@@ -0,0 +1,197 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// memory-calculator runs the JVM memory calculation offline, outside of a container, so that container sizing can be
+// checked ahead of deployment.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+
+	"github.com/paketo-buildpacks/libjvm"
+	"github.com/paketo-buildpacks/libjvm/calc"
+	"github.com/paketo-buildpacks/libjvm/count"
+	"github.com/paketo-buildpacks/libjvm/helper"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer, errOut io.Writer) error {
+	var (
+		f = flag.NewFlagSet("memory-calculator", flag.ContinueOnError)
+
+		totalMemory      = f.String("total-memory", "1G", "total memory available to the container, e.g. 512M or 2G")
+		threadCount      = f.Int("thread-count", helper.DefaultThreadCount, "number of threads the application will use")
+		loadedClassCount = f.Int("loaded-class-count", 0, "number of classes that will be loaded, counted from the application if not set")
+		headRoom         = f.Int("head-room", helper.DefaultHeadroom, "percentage of total memory that is not allocated to the JVM")
+		heapPercentage   = f.Float64("heap-percentage", 0, "percentage of total memory to use for the heap, the remaining memory if not set")
+		profile          = f.String("profile", "", "memory profile to apply, one of throughput, latency or batch")
+		jvmOptions       = f.String("jvm-options", "", "JVM options, equivalent to $JAVA_TOOL_OPTIONS")
+		jvmClassCount    = f.Int("jvm-class-count", 0, "number of classes in the JVM, required with --jvm-path unless --loaded-class-count is set")
+		jvmPath          = f.String("jvm-path", "", "path to a JVM whose classes are counted if --jvm-class-count is not set")
+		classAdjustment  = f.String("class-adjustment", "100%", "adjustment to the counted classes, either a percentage, e.g. 120%, or a number of classes to add, equivalent to $BPL_JVM_CLASS_ADJUSTMENT")
+		applicationPath  = f.String("application-path", "", "path to the application, may also be passed as the only argument")
+		report           = f.String("report", "", "print a full report instead of flags, one of json or text")
+	)
+
+	f.SetOutput(errOut)
+	f.Usage = func() {
+		fmt.Fprintf(f.Output(), "Usage: memory-calculator [flags] [application-path]\n")
+		f.PrintDefaults()
+	}
+
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+
+	if *applicationPath == "" && f.NArg() > 0 {
+		*applicationPath = f.Arg(0)
+	}
+
+	explicit := map[string]bool{}
+	f.Visit(func(fl *flag.Flag) { explicit[fl.Name] = true })
+
+	var evidence []string
+	if !explicit["thread-count"] && *applicationPath != "" {
+		e, err := libjvm.EstimateThreadCount(*applicationPath)
+		if err != nil {
+			return fmt.Errorf("unable to estimate thread count\n%w", err)
+		}
+		if len(e.Evidence) > 0 {
+			*threadCount, evidence = e.Count, e.Evidence
+		}
+	}
+
+	t, err := calc.ParseSize(*totalMemory)
+	if err != nil {
+		return fmt.Errorf("unable to parse --total-memory %s\n%w", *totalMemory, err)
+	}
+
+	var p calc.Profile
+	if *profile != "" {
+		if p, err = calc.ParseProfile(*profile); err != nil {
+			return err
+		}
+	}
+
+	c := calc.Calculator{
+		HeadRoom:         *headRoom,
+		HeapPercentage:   *heapPercentage,
+		LoadedClassCount: *loadedClassCount,
+		Profile:          p,
+		ThreadCount:      *threadCount,
+		TotalMemory:      t,
+	}
+
+	r := helper.MemoryCalculatorReport{
+		TotalMemory:         t.Value,
+		MemoryLimitSource:   "--total-memory",
+		ThreadCount:         c.ThreadCount,
+		ThreadCountEvidence: evidence,
+		HeadRoom:            c.HeadRoom,
+		HeapPercentage:      c.HeapPercentage,
+		Profile:             p.Name,
+		ClassCount:          helper.MemoryCalculatorReportClasses{Provenance: calc.UserConfigured},
+	}
+
+	if c.LoadedClassCount == 0 {
+		if *applicationPath == "" {
+			return fmt.Errorf("--application-path must be set when --loaded-class-count is not set")
+		}
+
+		if !explicit["jvm-class-count"] && *jvmPath == "" {
+			return fmt.Errorf("--jvm-class-count or --jvm-path must be set when --loaded-class-count is not set")
+		}
+
+		if !explicit["jvm-class-count"] {
+			if *jvmClassCount, err = count.Classes(*jvmPath); err != nil {
+				return fmt.Errorf("unable to count JVM classes in %s\n%w", *jvmPath, err)
+			}
+		}
+
+		staticAdjustment := 0
+		adjustmentFactor := uint64(100)
+		if strings.HasSuffix(*classAdjustment, "%") {
+			if adjustmentFactor, err = strconv.ParseUint(strings.TrimSuffix(*classAdjustment, "%"), 10, 32); err != nil {
+				return fmt.Errorf("unable to parse --class-adjustment %s as a percentage\n%w", *classAdjustment, err)
+			}
+		} else if staticAdjustment, err = strconv.Atoi(*classAdjustment); err != nil {
+			return fmt.Errorf("unable to parse --class-adjustment %s as an integer\n%w", *classAdjustment, err)
+		}
+
+		h := helper.MemoryCalculator{Logger: bard.NewLogger(errOut)}
+
+		appClassCount, err := h.CountApplicationClasses(*applicationPath, false)
+		if err != nil {
+			return fmt.Errorf("unable to count application classes in %s\n%w", *applicationPath, err)
+		}
+
+		agentClassCount, err := h.CountAgentClasses(*jvmOptions)
+		if err != nil {
+			return err
+		}
+
+		totalClasses := float64(*jvmClassCount+appClassCount+agentClassCount+staticAdjustment) * (float64(adjustmentFactor) / 100.0)
+		c.LoadedClassCount = int(totalClasses * helper.ClassLoadFactor)
+
+		r.ClassCount = helper.MemoryCalculatorReportClasses{
+			Provenance:       calc.Calculated,
+			JVM:              *jvmClassCount,
+			Application:      appClassCount,
+			Agent:            agentClassCount,
+			StaticAdjustment: staticAdjustment,
+			AdjustmentFactor: adjustmentFactor,
+			LoadFactor:       helper.ClassLoadFactor,
+		}
+	}
+	r.ClassCount.Loaded = c.LoadedClassCount
+
+	m, err := c.Calculate(*jvmOptions)
+	if err != nil {
+		return fmt.Errorf("unable to calculate memory configuration\n%w", err)
+	}
+
+	for _, w := range m.Warnings {
+		fmt.Fprintf(errOut, "WARNING: %s\n", w)
+	}
+
+	if *report != "" {
+		r.Regions = helper.NewMemoryCalculatorReportRegions(m.Regions())
+		return r.WriteFormat(*report, out)
+	}
+
+	calculated := m.Flags()
+	pf, err := p.Flags(*jvmOptions)
+	if err != nil {
+		return fmt.Errorf("unable to apply memory profile %s\n%w", p.Name, err)
+	}
+	calculated = append(calculated, pf...)
+
+	_, err = fmt.Fprintln(out, strings.Join(calculated, " "))
+	return err
+}
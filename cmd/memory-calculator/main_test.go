@@ -0,0 +1,138 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/calc"
+	"github.com/paketo-buildpacks/libjvm/helper"
+)
+
+func testRun(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+		out     *bytes.Buffer
+		errOut  *bytes.Buffer
+	)
+
+	it.Before(func() {
+		appPath = t.TempDir()
+		out = &bytes.Buffer{}
+		errOut = &bytes.Buffer{}
+	})
+
+	it("prints calculated flags", func() {
+		Expect(run([]string{"--loaded-class-count", "10000", "--thread-count", "100", "--total-memory", "1G"}, out, errOut)).To(Succeed())
+
+		Expect(out.String()).To(Equal("-XX:MaxDirectMemorySize=10M -Xmx619863K -XX:MaxMetaspaceSize=70312K -XX:ReservedCodeCacheSize=240M -Xss1M\n"))
+	})
+
+	it("does not print user configured flags", func() {
+		Expect(run([]string{"--loaded-class-count", "10000", "--thread-count", "100", "--jvm-options", "-Xmx512M"}, out, errOut)).To(Succeed())
+
+		Expect(out.String()).To(Equal("-XX:MaxDirectMemorySize=10M -XX:MaxMetaspaceSize=70312K -XX:ReservedCodeCacheSize=240M -Xss1M\n"))
+	})
+
+	it("counts application classes", func() {
+		Expect(os.WriteFile(filepath.Join(appPath, "alpha.class"), []byte{}, 0644)).To(Succeed())
+
+		Expect(run([]string{"--jvm-class-count", "5000", "--thread-count", "100", "--report", "json", appPath}, out, errOut)).To(Succeed())
+
+		var r helper.MemoryCalculatorReport
+		Expect(json.Unmarshal(out.Bytes(), &r)).To(Succeed())
+		Expect(r.ClassCount.Provenance).To(Equal(calc.Calculated))
+		Expect(r.ClassCount.JVM).To(Equal(5000))
+		Expect(r.ClassCount.Application).To(Equal(1))
+		Expect(r.ClassCount.Loaded).To(Equal(1750))
+	})
+
+	it("adjusts application classes by a percentage", func() {
+		Expect(run([]string{"--jvm-class-count", "5000", "--thread-count", "100", "--class-adjustment", "200%", "--report", "json", appPath}, out, errOut)).To(Succeed())
+
+		var r helper.MemoryCalculatorReport
+		Expect(json.Unmarshal(out.Bytes(), &r)).To(Succeed())
+		Expect(r.ClassCount.AdjustmentFactor).To(Equal(uint64(200)))
+		Expect(r.ClassCount.Loaded).To(Equal(3500))
+	})
+
+	it("adjusts application classes by a number of classes", func() {
+		Expect(run([]string{"--jvm-class-count", "5000", "--thread-count", "100", "--class-adjustment", "1000", "--report", "json", appPath}, out, errOut)).To(Succeed())
+
+		var r helper.MemoryCalculatorReport
+		Expect(json.Unmarshal(out.Bytes(), &r)).To(Succeed())
+		Expect(r.ClassCount.StaticAdjustment).To(Equal(1000))
+		Expect(r.ClassCount.AdjustmentFactor).To(Equal(uint64(100)))
+		Expect(r.ClassCount.Loaded).To(Equal(2100))
+	})
+
+	it("fails for an invalid class adjustment", func() {
+		Expect(run([]string{"--jvm-class-count", "5000", "--class-adjustment", "a%", appPath}, out, errOut)).
+			To(MatchError(HavePrefix("unable to parse --class-adjustment a% as a percentage")))
+	})
+
+	it("prints warnings to the error writer", func() {
+		Expect(run([]string{"--loaded-class-count", "100", "--thread-count", "2", "--head-room", "0", "--jvm-options", "-XX:+UseZGC -Xmx700M"}, out, errOut)).To(Succeed())
+
+		Expect(errOut.String()).To(HavePrefix("WARNING: estimated -XX:+UseZGC GC overhead of 105M means all memory regions may require"))
+		Expect(out.String()).NotTo(ContainSubstring("WARNING"))
+	})
+
+	it("estimates thread count from the application", func() {
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "lib"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "lib", "spring-webflux-6.1.0.jar"), []byte{}, 0644)).To(Succeed())
+
+		Expect(run([]string{"--loaded-class-count", "10000", "--report", "json", appPath}, out, errOut)).To(Succeed())
+
+		var r helper.MemoryCalculatorReport
+		Expect(json.Unmarshal(out.Bytes(), &r)).To(Succeed())
+		Expect(r.ThreadCount).To(Equal(100))
+		Expect(r.ThreadCountEvidence).To(Equal([]string{"spring-webflux-6.1.0.jar"}))
+	})
+
+	it("uses the default thread count without a framework", func() {
+		Expect(run([]string{"--loaded-class-count", "10000", "--report", "json", appPath}, out, errOut)).To(Succeed())
+
+		var r helper.MemoryCalculatorReport
+		Expect(json.Unmarshal(out.Bytes(), &r)).To(Succeed())
+		Expect(r.ThreadCount).To(Equal(helper.DefaultThreadCount))
+	})
+
+	it("requires the application without a loaded class count", func() {
+		Expect(run([]string{"--jvm-class-count", "5000"}, out, errOut)).
+			To(MatchError("--application-path must be set when --loaded-class-count is not set"))
+	})
+
+	it("requires the JVM class count without a loaded class count", func() {
+		Expect(run([]string{appPath}, out, errOut)).
+			To(MatchError("--jvm-class-count or --jvm-path must be set when --loaded-class-count is not set"))
+	})
+
+	it("fails for an unknown report format", func() {
+		Expect(run([]string{"--loaded-class-count", "10000", "--report", "xml"}, out, errOut)).
+			To(MatchError(ContainSubstring(`unknown report format "xml"`)))
+	})
+}
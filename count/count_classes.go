@@ -25,24 +25,59 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
 var ClassExtensions = []string{".class", ".classdata", ".clj", ".groovy", ".kts"}
 
+// ErrNestedJarTooLarge is returned when a compressed nested archive is too large to be decompressed into memory.
+var ErrNestedJarTooLarge = errors.New("compressed nested jar is larger than the maximum buffer size")
+
+// MaxNestedJarDepth is the deepest level of nested archives that classes are counted in.
+const MaxNestedJarDepth = 4
+
+// MaxNestedJarBufferSize is the largest compressed nested archive that is decompressed into memory to be counted.
+var MaxNestedJarBufferSize int64 = 64 * 1024 * 1024
+
 func Classes(path string) (int, error) {
+	c, _, err := ClassesAndSkippedJars(path)
+	return c, err
+}
+
+// ClassesAndSkippedJars counts the classes under path, also returning the number of compressed nested archives that
+// were skipped because they are larger than MaxNestedJarBufferSize.
+func ClassesAndSkippedJars(path string) (int, int, error) {
 	file := filepath.Join(path, "lib", "modules")
 	if _, err := os.Stat(file); err != nil && !os.IsNotExist(err) {
-		return 0, fmt.Errorf("unable to stat %s\n%w", file, err)
+		return 0, 0, fmt.Errorf("unable to stat %s\n%w", file, err)
 	} else if os.IsNotExist(err) {
-		return JarClasses(path)
-	} else {
-		return ModuleClasses(file)
+		return jarClasses(path)
+	}
+
+	c, err := ModuleClasses(file)
+	if errors.Is(err, ErrNotJImage) || errors.Is(err, ErrUnsupportedJImageVersion) {
+		// an unreadable image still leaves any JARs to be counted
+		return jarClasses(path)
 	}
+	return c, 0, err
 }
 
+// JarClasses counts the classes under path, including those in JARs. JARs are counted concurrently by a pool of
+// workers sized to GOMAXPROCS, which respects the container's CPU quota. Errors from individual JARs are aggregated
+// rather than stopping the count at the first failure.
 func JarClasses(path string) (int, error) {
-	count := 0
+	c, _, err := jarClasses(path)
+	return c, err
+}
+
+func jarClasses(path string) (int, int, error) {
+	var (
+		count   int
+		skipped int
+		jars    []string
+	)
 
 	if err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -67,33 +102,74 @@ func JarClasses(path string) (int, error) {
 			return nil
 		}
 
-		z, err := zip.OpenReader(path)
-		if err != nil {
-			if !(errors.Is(err, zip.ErrFormat)) {
-				return fmt.Errorf("unable to open Jar %s\n%w", path, err)
-			} else {
-				return nil
-			}
-		}
-		defer z.Close()
-
-		for _, f := range z.File {
-			if strings.HasSuffix(f.FileInfo().Name(), ".jar") {
-				c, err := nestedJarContents(f)
-				if err != nil {
-					return fmt.Errorf("unable to counted nested jar%w\n", err)
-				}
-				count += c
+		jars = append(jars, path)
+		return nil
+	}); err != nil {
+		return 0, 0, fmt.Errorf("unable to walk %s\n%w", path, err)
+	}
+
+	counts := make([]int, len(jars))
+	skips := make([]int, len(jars))
+	errs := make([]error, len(jars))
+	indexes := make(chan int)
+
+	var workers sync.WaitGroup
+	for i := 0; i < min(runtime.GOMAXPROCS(0), len(jars)); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for j := range indexes {
+				counts[j], skips[j], errs[j] = jarFileClasses(jars[j])
 			}
-			count += jarContents(f)
+		}()
+	}
+
+	for i := range jars {
+		indexes <- i
+	}
+	close(indexes)
+	workers.Wait()
+
+	for i, c := range counts {
+		count += c
+		skipped += skips[i]
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return 0, 0, fmt.Errorf("unable to count classes in %s\n%w", path, err)
+	}
+
+	return count, skipped, nil
+}
+
+func jarFileClasses(path string) (int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to open Jar %s\n%w", path, err)
+	}
+	defer f.Close()
+
+	// stat the opened file rather than the path, which may be a symlink
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to stat Jar %s\n%w", path, err)
+	}
+
+	z, err := zip.NewReader(f, fi.Size())
+	if err != nil {
+		if !(errors.Is(err, zip.ErrFormat)) {
+			return 0, 0, fmt.Errorf("unable to open Jar %s\n%w", path, err)
+		} else {
+			return 0, 0, nil
 		}
+	}
 
-		return nil
-	}); err != nil {
-		return 0, fmt.Errorf("unable to walk %s\n%w", path, err)
+	c, skipped, err := archiveClasses(f, z, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to count classes in Jar %s\n%w", path, err)
 	}
 
-	return count, nil
+	return c, skipped, nil
 }
 
 func ModuleClasses(file string) (int, error) {
@@ -152,31 +228,78 @@ func jarContents(file *zip.File) int {
 	return count
 }
 
-func nestedJarContents(jarFile *zip.File) (int, error) {
-	var count = 0
+// archiveClasses counts the classes in an archive read from r, including those in nested archives, and the nested
+// archives that were skipped.
+func archiveClasses(r io.ReaderAt, z *zip.Reader, depth int) (int, int, error) {
+	count, skipped := 0, 0
 
-	reader, err := jarFile.Open()
-	if err != nil {
-		return 0, fmt.Errorf("unable to open nested jar%w\n", err)
+	for _, f := range z.File {
+		if strings.HasSuffix(f.FileInfo().Name(), ".jar") && depth < MaxNestedJarDepth {
+			c, s, err := nestedJarContents(r, f, depth+1)
+			if err != nil {
+				return 0, 0, fmt.Errorf("unable to counted nested jar%w\n", err)
+			}
+			count += c
+			skipped += s
+		}
+		count += jarContents(f)
 	}
-	defer reader.Close()
 
-	var b bytes.Buffer
-	size, err := io.Copy(&b, reader)
-	if err != nil {
-		return 0, fmt.Errorf("error copying nested Jar \n%w", err)
+	return count, skipped, nil
+}
+
+// nestedJarContents counts the classes in a nested archive, skipping compressed archives too large to decompress.
+func nestedJarContents(r io.ReaderAt, jarFile *zip.File, depth int) (int, int, error) {
+	nr, nj, err := OpenNestedJar(r, jarFile)
+	if errors.Is(err, ErrNestedJarTooLarge) {
+		return 0, 1, nil
+	} else if err != nil {
+		return 0, 0, err
+	} else if nj == nil {
+		return 0, 0, nil
 	}
-	br := bytes.NewReader(b.Bytes())
-	nj, err := zip.NewReader(br, size)
-	if err != nil {
-		if !(errors.Is(err, zip.ErrFormat)) {
-			return 0, fmt.Errorf("error reading nested Jar contents\n%w", err)
-		} else {
-			return 0, nil
+
+	return archiveClasses(nr, nj, depth)
+}
+
+// OpenNestedJar opens an archive nested in the archive read from r. Stored (uncompressed) archives, such as those in
+// Spring Boot's BOOT-INF/lib, are read in place from the enclosing archive. Compressed archives must be decompressed
+// into memory and ErrNestedJarTooLarge is returned if they are larger than MaxNestedJarBufferSize. A nil archive is
+// returned if the entry is not an archive. The returned reader is the one that the nested archive is read from.
+func OpenNestedJar(r io.ReaderAt, jarFile *zip.File) (io.ReaderAt, *zip.Reader, error) {
+	var (
+		nr   io.ReaderAt
+		size = int64(jarFile.UncompressedSize64)
+	)
+
+	if jarFile.Method == zip.Store {
+		offset, err := jarFile.DataOffset()
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to find nested jar data\n%w", err)
 		}
+		nr = io.NewSectionReader(r, offset, size)
+	} else if jarFile.UncompressedSize64 <= uint64(MaxNestedJarBufferSize) {
+		reader, err := jarFile.Open()
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open nested jar\n%w", err)
+		}
+		defer reader.Close()
+
+		b, err := io.ReadAll(io.LimitReader(reader, MaxNestedJarBufferSize))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error copying nested Jar \n%w", err)
+		}
+		nr, size = bytes.NewReader(b), int64(len(b))
+	} else {
+		return nil, nil, fmt.Errorf("unable to open %s\n%w", jarFile.Name, ErrNestedJarTooLarge)
 	}
-	for _, nestedJar := range nj.File {
-		count += jarContents(nestedJar)
+
+	nj, err := zip.NewReader(nr, size)
+	if errors.Is(err, zip.ErrFormat) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("error reading nested Jar contents\n%w", err)
 	}
-	return count, nil
+
+	return nr, nj, nil
 }
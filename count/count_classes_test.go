@@ -17,9 +17,16 @@
 package count_test
 
 import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -66,6 +73,62 @@ func testCountClasses(t *testing.T, context spec.G, it spec.S) {
 		Expect(count.Classes("testdata")).To(Equal(6))
 	})
 
+	it("counts files in stored nested archives", func() {
+		inner := zipBytes(t, zip.Deflate, map[string][]byte{"alpha.class": {}, "bravo.class": {}})
+		Expect(ioutil.WriteFile(filepath.Join(path, "outer.jar"),
+			zipBytes(t, zip.Store, map[string][]byte{"BOOT-INF/lib/inner.jar": inner, "charlie.class": {}}), 0644)).To(Succeed())
+
+		Expect(count.Classes(path)).To(Equal(3))
+	})
+
+	it("opens stored nested archives in place", func() {
+		inner := zipBytes(t, zip.Deflate, map[string][]byte{"alpha.class": {}})
+		outer := zipBytes(t, zip.Store, map[string][]byte{"inner.jar": inner, "bravo.txt": []byte("bravo")})
+
+		z, err := zip.NewReader(bytes.NewReader(outer), int64(len(outer)))
+		Expect(err).NotTo(HaveOccurred())
+
+		r, nz, err := count.OpenNestedJar(bytes.NewReader(outer), z.File[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r).To(BeAssignableToTypeOf(&io.SectionReader{}))
+		Expect(nz.File).To(HaveLen(1))
+
+		_, nz, err = count.OpenNestedJar(bytes.NewReader(outer), z.File[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nz).To(BeNil())
+	})
+
+	it("reports compressed nested archives too large to count", func() {
+		defer func(size int64) { count.MaxNestedJarBufferSize = size }(count.MaxNestedJarBufferSize)
+		count.MaxNestedJarBufferSize = 1024
+
+		inner := zipBytes(t, zip.Store, map[string][]byte{"alpha.class": {}, "padding": make([]byte, count.MaxNestedJarBufferSize)})
+		Expect(ioutil.WriteFile(filepath.Join(path, "outer.jar"),
+			zipBytes(t, zip.Deflate, map[string][]byte{"inner.jar": inner, "bravo.class": {}}), 0644)).To(Succeed())
+
+		c, skipped, err := count.ClassesAndSkippedJars(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).To(Equal(1))
+		Expect(skipped).To(Equal(1))
+	})
+
+	it("counts files in archives nested more than one level down", func() {
+		innermost := zipBytes(t, zip.Deflate, map[string][]byte{"alpha.class": {}})
+		inner := zipBytes(t, zip.Store, map[string][]byte{"innermost.jar": innermost, "bravo.class": {}})
+		Expect(ioutil.WriteFile(filepath.Join(path, "outer.jar"),
+			zipBytes(t, zip.Deflate, map[string][]byte{"inner.jar": inner}), 0644)).To(Succeed())
+
+		Expect(count.Classes(path)).To(Equal(2))
+	})
+
+	it("counts files in symlinked archives", func() {
+		abs, err := filepath.Abs("testdata/stub-dependency.jar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Symlink(abs, filepath.Join(path, "link.jar"))).To(Succeed())
+
+		Expect(count.Classes(path)).To(Equal(2))
+	})
+
 	it("skips empty zip/jar files with none in the name", func() {
 		Expect(ioutil.WriteFile(filepath.Join(path, "test-none.jar"), []byte{}, 0644)).To(Succeed())
 
@@ -83,4 +146,55 @@ func testCountClasses(t *testing.T, context spec.G, it spec.S) {
 
 		Expect(count.Classes(path)).To(Equal(0))
 	})
+
+	it("counts files across many archives", func() {
+		for i := 0; i < 50; i++ {
+			Expect(ioutil.WriteFile(filepath.Join(path, fmt.Sprintf("archive-%d.jar", i)),
+				zipBytes(t, zip.Deflate, map[string][]byte{"alpha.class": {}, "bravo.class": {}}), 0644)).To(Succeed())
+		}
+		Expect(ioutil.WriteFile(filepath.Join(path, "charlie.class"), []byte{}, 0644)).To(Succeed())
+
+		for i := 0; i < 5; i++ {
+			Expect(count.Classes(path)).To(Equal(101))
+		}
+	})
+
+	it("reports errors from every archive", func() {
+		Expect(ioutil.WriteFile(filepath.Join(path, "good.jar"),
+			zipBytes(t, zip.Deflate, map[string][]byte{"alpha.class": {}}), 0644)).To(Succeed())
+		Expect(os.Symlink(filepath.Join(path, "missing-alpha"), filepath.Join(path, "alpha.jar"))).To(Succeed())
+		Expect(os.Symlink(filepath.Join(path, "missing-bravo"), filepath.Join(path, "bravo.jar"))).To(Succeed())
+
+		_, err := count.Classes(path)
+		Expect(err).To(MatchError(ContainSubstring(filepath.Join(path, "alpha.jar"))))
+		Expect(err).To(MatchError(ContainSubstring(filepath.Join(path, "bravo.jar"))))
+		Expect(errors.Is(err, fs.ErrNotExist)).To(BeTrue())
+	})
+}
+
+func zipBytes(t *testing.T, method uint16, files map[string][]byte) []byte {
+	t.Helper()
+
+	var names []string
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	b := &bytes.Buffer{}
+	z := zip.NewWriter(b)
+	for _, n := range names {
+		w, err := z.CreateHeader(&zip.FileHeader{Name: n, Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(files[n]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
 }
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package count
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// Fingerprint returns a hash of the names, sizes and modes of every file under path and the central directory, listing
+// the name, CRC-32 and size of every entry, of every JAR.  Modification times are not included as they are normalized
+// when images are exported.  No file content other than the central directory of a JAR is read, so a fingerprint is
+// cheap enough to compute at launch to decide whether a count made at build time is still valid.
+func Fingerprint(path string) (string, error) {
+	h := sha256.New()
+
+	if err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		return fingerprintEntry(h, path, p, d)
+	}); err != nil {
+		return "", fmt.Errorf("unable to walk %s\n%w", path, err)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func fingerprintEntry(h hash.Hash, root string, path string, d fs.DirEntry) error {
+	i, err := d.Info()
+	if err != nil {
+		return fmt.Errorf("unable to stat %s\n%w", path, err)
+	}
+
+	r, err := filepath.Rel(root, path)
+	if err != nil {
+		return fmt.Errorf("unable to relativize %s\n%w", path, err)
+	}
+
+	size := i.Size()
+	if i.IsDir() {
+		size = 0
+	}
+
+	if _, err = fmt.Fprintf(h, "%s\x00%d\x00%s\n", filepath.ToSlash(r), size, i.Mode()); err != nil {
+		return err
+	}
+
+	if !i.Mode().IsRegular() || !strings.HasSuffix(path, ".jar") {
+		return nil
+	}
+
+	return fingerprintJar(h, path)
+}
+
+func fingerprintJar(h hash.Hash, path string) error {
+	z, err := zip.OpenReader(path)
+	if errors.Is(err, zip.ErrFormat) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to open %s\n%w", path, err)
+	}
+	defer z.Close()
+
+	for _, f := range z.File {
+		if _, err := fmt.Fprintf(h, "\x00%s\x00%08x\x00%d\n", f.Name, f.CRC32, f.UncompressedSize64); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package count_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+func testFingerprint(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(path, "alpha.class"), []byte("alpha"), 0644)).To(Succeed())
+	})
+
+	it("is stable", func() {
+		a, err := count.Fingerprint(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(count.Fingerprint(path)).To(Equal(a))
+	})
+
+	it("ignores modification times", func() {
+		a, err := count.Fingerprint(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.Chtimes(filepath.Join(path, "alpha.class"), time.Unix(0, 0), time.Unix(0, 0))).To(Succeed())
+
+		Expect(count.Fingerprint(path)).To(Equal(a))
+	})
+
+	it("changes when a file is added", func() {
+		a, err := count.Fingerprint(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.WriteFile(filepath.Join(path, "bravo.class"), []byte{}, 0644)).To(Succeed())
+
+		Expect(count.Fingerprint(path)).NotTo(Equal(a))
+	})
+
+	it("changes when a file changes size", func() {
+		a, err := count.Fingerprint(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.WriteFile(filepath.Join(path, "alpha.class"), []byte("alpha-bravo"), 0644)).To(Succeed())
+
+		Expect(count.Fingerprint(path)).NotTo(Equal(a))
+	})
+
+	it("does not read class contents", func() {
+		a, err := count.Fingerprint(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.WriteFile(filepath.Join(path, "alpha.class"), []byte("bravo"), 0644)).To(Succeed())
+
+		Expect(count.Fingerprint(path)).To(Equal(a))
+	})
+
+	it("changes when a JAR entry changes content", func() {
+		jar := func(content string) {
+			b := &bytes.Buffer{}
+			z := zip.NewWriter(b)
+			w, err := z.CreateHeader(&zip.FileHeader{Name: "alpha.class", Method: zip.Store})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(z.Close()).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(path, "alpha.jar"), b.Bytes(), 0644)).To(Succeed())
+		}
+
+		jar("alpha")
+		a, err := count.Fingerprint(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		jar("bravo")
+		Expect(count.Fingerprint(path)).NotTo(Equal(a))
+	})
+}
@@ -18,6 +18,7 @@ package count
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 )
@@ -25,6 +26,20 @@ import (
 const (
 	HeaderSlots = 7
 	HeaderSize  = 4
+
+	// Magic is the magic number at the start of every jimage.
+	Magic uint32 = 0xCAFEDADA
+
+	// SupportedMajorVersion is the jimage major version that can be read.
+	SupportedMajorVersion = 1
+)
+
+var (
+	// ErrNotJImage is returned when a file is not a jimage, or is too short to be one.
+	ErrNotJImage = errors.New("not a jimage")
+
+	// ErrUnsupportedJImageVersion is returned when a jimage has a major version that cannot be read.
+	ErrUnsupportedJImageVersion = errors.New("unsupported jimage version")
 )
 
 type Header struct {
@@ -36,45 +51,69 @@ type Header struct {
 	TableLength   int32
 	LocationsSize int32
 	StringsSize   int32
+
+	// ByteOrder is the byte order of the image, as signalled by its magic number.
+	ByteOrder binary.ByteOrder
 }
 
 func NewHeader(reader io.Reader) (Header, error) {
 	var h Header
 
-	if err := binary.Read(reader, binary.LittleEndian, &h.Magic); err != nil {
-		return Header{}, fmt.Errorf("unable to read magic\n%w", err)
+	var magic [4]byte
+	if _, err := io.ReadFull(reader, magic[:]); err != nil {
+		return Header{}, fmt.Errorf("unable to read magic: %w\n%w", ErrNotJImage, err)
 	}
 
+	switch {
+	case binary.LittleEndian.Uint32(magic[:]) == Magic:
+		h.ByteOrder = binary.LittleEndian
+	case binary.BigEndian.Uint32(magic[:]) == Magic:
+		h.ByteOrder = binary.BigEndian
+	default:
+		return Header{}, fmt.Errorf("%w: magic 0x%X does not match 0x%X", ErrNotJImage, magic[:], Magic)
+	}
+	h.Magic = int32(h.ByteOrder.Uint32(magic[:]))
+
 	var version int32
-	if err := binary.Read(reader, binary.LittleEndian, &version); err != nil {
+	if err := binary.Read(reader, h.ByteOrder, &version); err != nil {
 		return Header{}, fmt.Errorf("unable to read version\n%w", err)
 	}
 	h.MajorVersion = version >> 16
 	h.MinorVersion = version & 0xFFFF
 
-	if err := binary.Read(reader, binary.LittleEndian, &h.Flags); err != nil {
+	if h.MajorVersion != SupportedMajorVersion {
+		return Header{}, fmt.Errorf("%w: %d.%d, must be %d.x",
+			ErrUnsupportedJImageVersion, h.MajorVersion, h.MinorVersion, SupportedMajorVersion)
+	}
+
+	if err := binary.Read(reader, h.ByteOrder, &h.Flags); err != nil {
 		return Header{}, fmt.Errorf("unable to read flags\n%w", err)
 	}
 
-	if err := binary.Read(reader, binary.LittleEndian, &h.ResourceCount); err != nil {
+	if err := binary.Read(reader, h.ByteOrder, &h.ResourceCount); err != nil {
 		return Header{}, fmt.Errorf("unable to read resource count\n%w", err)
 	}
 
-	if err := binary.Read(reader, binary.LittleEndian, &h.TableLength); err != nil {
+	if err := binary.Read(reader, h.ByteOrder, &h.TableLength); err != nil {
 		return Header{}, fmt.Errorf("unable to read table length\n%w", err)
 	}
 
-	if err := binary.Read(reader, binary.LittleEndian, &h.LocationsSize); err != nil {
+	if err := binary.Read(reader, h.ByteOrder, &h.LocationsSize); err != nil {
 		return Header{}, fmt.Errorf("unable to read locations size\n%w", err)
 	}
 
-	if err := binary.Read(reader, binary.LittleEndian, &h.StringsSize); err != nil {
+	if err := binary.Read(reader, h.ByteOrder, &h.StringsSize); err != nil {
 		return Header{}, fmt.Errorf("unable to read strings size\n%w", err)
 	}
 
 	return h, nil
 }
 
+// IndexSize returns the size of the index, which comprises the header, redirects, offsets, locations, and strings.
+func (h Header) IndexSize() int64 {
+	return int64(h.Size()) + int64(h.TableLength)*(RedirectSize+OffsetSize) + int64(h.LocationsSize) + int64(h.StringsSize)
+}
+
 func (Header) Size() uint32 {
 	return HeaderSlots * HeaderSize
 }
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package count_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+func testHeader(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "header")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("reads little-endian header", func() {
+		h, err := count.NewHeader(bytes.NewReader(jimageBytes(t, binary.LittleEndian, resource{"/java.base/java/lang/Object.class", 1})))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(h.ByteOrder).To(Equal(binary.LittleEndian))
+		Expect(h.MajorVersion).To(Equal(int32(1)))
+		Expect(h.ResourceCount).To(Equal(int32(1)))
+	})
+
+	it("reads big-endian header", func() {
+		h, err := count.NewHeader(bytes.NewReader(jimageBytes(t, binary.BigEndian, resource{"/java.base/java/lang/Object.class", 1})))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(h.ByteOrder).To(Equal(binary.BigEndian))
+		Expect(h.MajorVersion).To(Equal(int32(1)))
+		Expect(h.ResourceCount).To(Equal(int32(1)))
+	})
+
+	it("counts classes in big-endian image", func() {
+		file := filepath.Join(path, "modules")
+		Expect(ioutil.WriteFile(file, jimageBytes(t, binary.BigEndian,
+			resource{"/java.base/java/lang/Object.class", 1},
+			resource{"/java.base/java/lang/String.class", 1},
+		), 0644)).To(Succeed())
+
+		Expect(count.ModuleClasses(file)).To(Equal(2))
+	})
+
+	it("rejects incorrect magic", func() {
+		_, err := count.NewHeader(bytes.NewReader([]byte("PK\x03\x04 not a jimage at all")))
+		Expect(err).To(MatchError(count.ErrNotJImage))
+	})
+
+	it("rejects empty file", func() {
+		_, err := count.NewHeader(bytes.NewReader(nil))
+		Expect(err).To(MatchError(count.ErrNotJImage))
+	})
+
+	it("rejects unsupported version", func() {
+		b := jimageBytes(t, binary.LittleEndian, resource{"/java.base/java/lang/Object.class", 1})
+		binary.LittleEndian.PutUint32(b[4:], 2<<16)
+
+		_, err := count.NewHeader(bytes.NewReader(b))
+		Expect(err).To(MatchError(count.ErrUnsupportedJImageVersion))
+	})
+
+	it("rejects truncated image", func() {
+		b := jimageBytes(t, binary.LittleEndian, resource{"/java.base/java/lang/Object.class", 1})
+		file := filepath.Join(path, "modules")
+		Expect(ioutil.WriteFile(file, b[:len(b)-4], 0644)).To(Succeed())
+
+		_, err := count.NewImage(file)
+		Expect(err).To(MatchError(count.ErrNotJImage))
+	})
+
+	it("falls back to counting JARs when image is unreadable", func() {
+		Expect(os.MkdirAll(filepath.Join(path, "lib"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(path, "lib", "modules"), []byte("garbage"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(path, "lib", "alpha.jar"),
+			zipBytes(t, zip.Deflate, map[string][]byte{"alpha.class": {}, "bravo.class": {}}), 0644)).To(Succeed())
+
+		Expect(count.Classes(path)).To(Equal(2))
+	})
+}
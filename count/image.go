@@ -17,6 +17,7 @@
 package count
 
 import (
+	"encoding/binary"
 	"fmt"
 	"os"
 )
@@ -26,6 +27,7 @@ type Image struct {
 	MinorVersion  int32
 	Flags         int32
 	ResourceCount int32
+	ByteOrder     binary.ByteOrder
 
 	Redirects Redirects
 	Offsets   Offsets
@@ -46,17 +48,28 @@ func NewImage(path string) (Image, error) {
 		return Image{}, fmt.Errorf("unable to read header\n%w", err)
 	}
 
+	s, err := in.Stat()
+	if err != nil {
+		return Image{}, fmt.Errorf("unable to stat %s\n%w", path, err)
+	}
+
+	if h.TableLength < 0 || h.LocationsSize < 0 || h.StringsSize < 0 || h.IndexSize() > s.Size() {
+		return Image{}, fmt.Errorf("%w: index of %d bytes does not fit in %s of %d bytes",
+			ErrNotJImage, h.IndexSize(), path, s.Size())
+	}
+
 	i.MajorVersion = h.MajorVersion
 	i.MinorVersion = h.MinorVersion
 	i.Flags = h.Flags
 	i.ResourceCount = h.ResourceCount
+	i.ByteOrder = h.ByteOrder
 
-	i.Redirects, err = NewRedirects(in, int32(h.Size()), h.TableLength)
+	i.Redirects, err = NewRedirects(in, h.ByteOrder, int32(h.Size()), h.TableLength)
 	if err != nil {
 		return Image{}, fmt.Errorf("unable to create redirects\n%w", err)
 	}
 
-	i.Offsets, err = NewOffsets(in, i.Redirects.Offset+int32(i.Redirects.Size()), h.TableLength)
+	i.Offsets, err = NewOffsets(in, h.ByteOrder, i.Redirects.Offset+int32(i.Redirects.Size()), h.TableLength)
 	if err != nil {
 		return Image{}, fmt.Errorf("unable to create offsets\n%w", err)
 	}
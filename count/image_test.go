@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package count_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+type resource struct {
+	name string
+	size int32
+}
+
+// jimageBytes returns a minimal jimage, in the given byte order, containing resources named /<module>/<parent>/<base>.<extension>.
+func jimageBytes(t *testing.T, order binary.ByteOrder, resources ...resource) []byte {
+	t.Helper()
+
+	s := &bytes.Buffer{}
+	s.WriteByte(0)
+	offsets := map[string]int32{"": 0}
+	str := func(v string) int32 {
+		if o, ok := offsets[v]; ok {
+			return o
+		}
+		o := int32(s.Len())
+		s.WriteString(v)
+		s.WriteByte(0)
+		offsets[v] = o
+		return o
+	}
+
+	l := &bytes.Buffer{}
+	attribute := func(kind byte, value int32) {
+		if value == 0 {
+			return
+		}
+		var b []byte
+		for v := uint32(value); v != 0; v >>= 8 {
+			b = append([]byte{byte(v)}, b...)
+		}
+		l.WriteByte(kind<<3 | byte(len(b)-1))
+		l.Write(b)
+	}
+
+	var entries []int32
+	for _, r := range resources {
+		parts := strings.SplitN(strings.TrimPrefix(r.name, "/"), "/", 2)
+		module, rest := parts[0], parts[1]
+
+		var parent string
+		if i := strings.LastIndex(rest, "/"); i >= 0 {
+			parent, rest = rest[:i], rest[i+1:]
+		}
+
+		var extension string
+		if i := strings.LastIndex(rest, "."); i >= 0 {
+			rest, extension = rest[:i], rest[i+1:]
+		}
+
+		entries = append(entries, int32(l.Len()))
+		attribute(count.AttributeModule, str(module))
+		attribute(count.AttributeParent, str(parent))
+		attribute(count.AttributeBase, str(rest))
+		attribute(count.AttributeExtension, str(extension))
+		attribute(count.AttributeUncompressed, r.size)
+		l.WriteByte(count.AttributeEnd)
+	}
+
+	b := &bytes.Buffer{}
+	write := func(v interface{}) {
+		if err := binary.Write(b, order, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(uint32(0xCAFEDADA))
+	write(int32(1 << 16))
+	write(int32(0))
+	write(int32(len(resources)))
+	write(int32(len(entries)))
+	write(int32(l.Len()))
+	write(int32(s.Len()))
+	write(make([]int32, len(entries)))
+	write(entries)
+	b.Write(l.Bytes())
+	b.Write(s.Bytes())
+
+	return b.Bytes()
+}
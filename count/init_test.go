@@ -26,5 +26,8 @@ import (
 func TestUnit(t *testing.T) {
 	suite := spec.New("libjvm/count", spec.Report(report.Terminal{}))
 	suite("CountClasses", testCountClasses)
+	suite("Fingerprint", testFingerprint)
+	suite("Header", testHeader)
+	suite("Inventory", testInventory)
 	suite.Run(t)
 }
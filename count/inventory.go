@@ -0,0 +1,134 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package count
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Module is the inventory of a single module in a jimage.
+type Module struct {
+	Name         string
+	Classes      int
+	Resources    int
+	ResourceSize int64
+	Packages     []Package
+}
+
+// Package is the inventory of a single package within a module in a jimage.  Resources outside of any package, such
+// as module-info.class, are recorded in a package with an empty name.
+type Package struct {
+	Name         string
+	Classes      int
+	Resources    int
+	ResourceSize int64
+}
+
+// ModuleInventory returns the classes and resources in the jimage at file, grouped by module and package.
+func ModuleInventory(file string) ([]Module, error) {
+	i, err := NewImage(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find JVM modules in %s\n%w", file, err)
+	}
+
+	return i.Inventory()
+}
+
+// Inventory returns the classes and resources in the image, grouped by module and package.  Modules and packages are
+// sorted by name.  Every entry is counted as a resource, and resource sizes are uncompressed sizes.
+func (i Image) Inventory() ([]Module, error) {
+	modules := make(map[string]map[string]*Package)
+
+	for _, o := range i.Offsets.Entries {
+		l, err := i.Locations.Get(o)
+		if err != nil {
+			return nil, fmt.Errorf("unable to inventory JVM modules\n%w", err)
+		}
+
+		var module, parent, extension string
+
+		if l.ModuleOffset != 0 {
+			if module, err = i.Strings.Get(l.ModuleOffset); err != nil {
+				return nil, fmt.Errorf("unable to get module name\n%w", err)
+			}
+		}
+
+		if l.ParentOffset != 0 {
+			if parent, err = i.Strings.Get(l.ParentOffset); err != nil {
+				return nil, fmt.Errorf("unable to get parent name\n%w", err)
+			}
+		}
+
+		if l.ExtensionOffset != 0 {
+			if extension, err = l.Extension(i.Strings); err != nil {
+				return nil, fmt.Errorf("unable to inventory JVM modules\n%w", err)
+			}
+		}
+
+		packages, ok := modules[module]
+		if !ok {
+			packages = make(map[string]*Package)
+			modules[module] = packages
+		}
+
+		name := strings.ReplaceAll(parent, "/", ".")
+		p, ok := packages[name]
+		if !ok {
+			p = &Package{Name: name}
+			packages[name] = p
+		}
+
+		p.Resources++
+		p.ResourceSize += int64(l.UncompressedSize)
+		if extension == "class" {
+			p.Classes++
+		}
+	}
+
+	var inventory []Module
+	for name, packages := range modules {
+		m := Module{Name: name}
+
+		for _, p := range packages {
+			m.Classes += p.Classes
+			m.Resources += p.Resources
+			m.ResourceSize += p.ResourceSize
+			m.Packages = append(m.Packages, *p)
+		}
+		sort.Slice(m.Packages, func(i, j int) bool {
+			return m.Packages[i].Name < m.Packages[j].Name
+		})
+
+		inventory = append(inventory, m)
+	}
+	sort.Slice(inventory, func(i, j int) bool {
+		return inventory[i].Name < inventory[j].Name
+	})
+
+	return inventory, nil
+}
+
+// ModuleNames returns the names of the modules in an inventory.
+func ModuleNames(modules []Module) []string {
+	var n []string
+	for _, m := range modules {
+		n = append(n, m.Name)
+	}
+	return n
+}
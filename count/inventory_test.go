@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package count_test
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+func testInventory(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "inventory")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ioutil.WriteFile(filepath.Join(path, "modules"), jimageBytes(t, binary.LittleEndian,
+			resource{"/java.base/module-info.class", 10},
+			resource{"/java.base/java/lang/Object.class", 100},
+			resource{"/java.base/java/lang/String.class", 200},
+			resource{"/java.base/java/util/List.class", 50},
+			resource{"/java.base/java/util/resources/messages.properties", 25},
+			resource{"/java.sql/module-info.class", 5},
+			resource{"/java.sql/java/sql/Driver.class", 40},
+		), 0644)).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("groups classes by module and package", func() {
+		i, err := count.ModuleInventory(filepath.Join(path, "modules"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(i).To(Equal([]count.Module{
+			{
+				Name:         "java.base",
+				Classes:      4,
+				Resources:    5,
+				ResourceSize: 385,
+				Packages: []count.Package{
+					{Name: "", Classes: 1, Resources: 1, ResourceSize: 10},
+					{Name: "java.lang", Classes: 2, Resources: 2, ResourceSize: 300},
+					{Name: "java.util", Classes: 1, Resources: 1, ResourceSize: 50},
+					{Name: "java.util.resources", Resources: 1, ResourceSize: 25},
+				},
+			},
+			{
+				Name:         "java.sql",
+				Classes:      2,
+				Resources:    2,
+				ResourceSize: 45,
+				Packages: []count.Package{
+					{Name: "", Classes: 1, Resources: 1, ResourceSize: 5},
+					{Name: "java.sql", Classes: 1, Resources: 1, ResourceSize: 40},
+				},
+			},
+		}))
+		Expect(count.ModuleNames(i)).To(Equal([]string{"java.base", "java.sql"}))
+	})
+
+	it("agrees with module class count", func() {
+		Expect(count.ModuleClasses(filepath.Join(path, "modules"))).To(Equal(6))
+	})
+}
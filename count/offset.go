@@ -30,7 +30,7 @@ type Offsets struct {
 	Offset  int32
 }
 
-func NewOffsets(reader io.ReadSeeker, offset int32, tableLength int32) (Offsets, error) {
+func NewOffsets(reader io.ReadSeeker, order binary.ByteOrder, offset int32, tableLength int32) (Offsets, error) {
 	o := Offsets{
 		Entries: make([]Offset, tableLength),
 		Offset:  offset,
@@ -41,7 +41,7 @@ func NewOffsets(reader io.ReadSeeker, offset int32, tableLength int32) (Offsets,
 	}
 
 	for i := 0; i < len(o.Entries); i++ {
-		if err := binary.Read(reader, binary.LittleEndian, &o.Entries[i]); err != nil {
+		if err := binary.Read(reader, order, &o.Entries[i]); err != nil {
 			return Offsets{}, fmt.Errorf("unable to read offset\n%w", err)
 		}
 	}
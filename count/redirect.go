@@ -30,7 +30,7 @@ type Redirects struct {
 	Offset  int32
 }
 
-func NewRedirects(reader io.ReadSeeker, offset int32, tableLength int32) (Redirects, error) {
+func NewRedirects(reader io.ReadSeeker, order binary.ByteOrder, offset int32, tableLength int32) (Redirects, error) {
 	r := Redirects{
 		Entries: make([]Redirect, tableLength),
 		Offset:  offset,
@@ -41,7 +41,7 @@ func NewRedirects(reader io.ReadSeeker, offset int32, tableLength int32) (Redire
 	}
 
 	for i := 0; i < len(r.Entries); i++ {
-		if err := binary.Read(reader, binary.LittleEndian, &r.Entries[i]); err != nil {
+		if err := binary.Read(reader, order, &r.Entries[i]); err != nil {
 			return Redirects{}, fmt.Errorf("unable to read redirect\n%w", err)
 		}
 	}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// AOTCache configures the JVM to use the AOT cache created at build time, as long as the runtime JVM is the one that
+// created it.
+type AOTCache struct {
+	Logger bard.Logger
+}
+
+func (a AOTCache) Execute() (map[string]string, error) {
+	cache, ok := os.LookupEnv("BPI_JVM_AOT_CACHE")
+	if !ok {
+		return nil, nil
+	}
+
+	opts := os.Getenv("JAVA_TOOL_OPTIONS")
+	if strings.Contains(opts, "-XX:AOTCache=") || strings.Contains(opts, "-XX:AOTMode=") {
+		a.Logger.Debug("AOT cache configured in $JAVA_TOOL_OPTIONS, skipping")
+		return nil, nil
+	}
+
+	if _, err := os.Stat(cache); err != nil {
+		a.Logger.Infof("WARNING: unable to find AOT cache %s, AOT cache disabled", cache)
+		return nil, nil
+	}
+
+	if ok, javaHome, err := matchesJVM(os.Getenv("BPI_JVM_AOT_JVM_FINGERPRINT")); err != nil {
+		return nil, err
+	} else if !ok {
+		a.Logger.Infof("WARNING: AOT cache %s was created by a different JVM than %s, AOT cache disabled", cache, javaHome)
+		return nil, nil
+	}
+
+	a.Logger.Infof("Using AOT cache %s", cache)
+
+	opts = sherpa.AppendToEnvVar("JAVA_TOOL_OPTIONS", " ", fmt.Sprintf("-XX:AOTCache=%s", cache))
+	return map[string]string{"JAVA_TOOL_OPTIONS": opts}, nil
+}
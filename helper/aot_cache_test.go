@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+	"github.com/paketo-buildpacks/libjvm/helper"
+)
+
+func testAOTCache(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		a = helper.AOTCache{}
+
+		cache    string
+		javaHome string
+	)
+
+	it.Before(func() {
+		javaHome = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(javaHome, "release"), []byte(`JAVA_VERSION="24.0.1"`), 0644)).To(Succeed())
+		t.Setenv("JAVA_HOME", javaHome)
+
+		cache = filepath.Join(t.TempDir(), "application.aot")
+		Expect(os.WriteFile(cache, []byte{}, 0644)).To(Succeed())
+	})
+
+	it("returns if $BPI_JVM_AOT_CACHE is not set", func() {
+		Expect(a.Execute()).To(BeNil())
+	})
+
+	context("$BPI_JVM_AOT_CACHE", func() {
+		it.Before(func() {
+			t.Setenv("BPI_JVM_AOT_CACHE", cache)
+
+			f, err := libjvm.JVMFingerprint(javaHome)
+			Expect(err).NotTo(HaveOccurred())
+			t.Setenv("BPI_JVM_AOT_JVM_FINGERPRINT", f)
+		})
+
+		it("contributes AOT cache", func() {
+			Expect(a.Execute()).To(Equal(map[string]string{
+				"JAVA_TOOL_OPTIONS": "-XX:AOTCache=" + cache,
+			}))
+		})
+
+		it("does not override user configured AOT mode", func() {
+			t.Setenv("JAVA_TOOL_OPTIONS", "-XX:AOTMode=off")
+
+			Expect(a.Execute()).To(BeNil())
+		})
+
+		it("disables AOT cache if the JVM has changed", func() {
+			Expect(os.WriteFile(filepath.Join(javaHome, "release"), []byte(`JAVA_VERSION="25.0.1"`), 0644)).To(Succeed())
+
+			Expect(a.Execute()).To(BeNil())
+		})
+	})
+}
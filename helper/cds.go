@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+// CDS configures the JVM to use the Class Data Sharing archive created at build time, as long as the runtime JVM is
+// the one that created it.
+type CDS struct {
+	Logger bard.Logger
+}
+
+func (c CDS) Execute() (map[string]string, error) {
+	archive, ok := os.LookupEnv("BPI_JVM_CDS_ARCHIVE")
+	if !ok {
+		return nil, nil
+	}
+
+	if strings.Contains(os.Getenv("JAVA_TOOL_OPTIONS"), "-XX:SharedArchiveFile=") {
+		c.Logger.Debug("CDS archive configured in $JAVA_TOOL_OPTIONS, skipping")
+		return nil, nil
+	}
+
+	if _, err := os.Stat(archive); err != nil {
+		c.Logger.Infof("WARNING: unable to find CDS archive %s, CDS disabled", archive)
+		return nil, nil
+	}
+
+	if ok, javaHome, err := matchesJVM(os.Getenv("BPI_JVM_CDS_JVM_FINGERPRINT")); err != nil {
+		return nil, err
+	} else if !ok {
+		c.Logger.Infof("WARNING: CDS archive %s was created by a different JVM than %s, CDS disabled", archive, javaHome)
+		return nil, nil
+	}
+
+	c.Logger.Infof("Using CDS archive %s", archive)
+
+	opts := sherpa.AppendToEnvVar("JAVA_TOOL_OPTIONS", " ", fmt.Sprintf("-XX:SharedArchiveFile=%s", archive))
+	return map[string]string{"JAVA_TOOL_OPTIONS": opts}, nil
+}
+
+// matchesJVM returns whether the JVM at $JAVA_HOME has the given fingerprint.
+func matchesJVM(fingerprint string) (bool, string, error) {
+	javaHome, ok := os.LookupEnv("JAVA_HOME")
+	if !ok {
+		return false, "", fmt.Errorf("$JAVA_HOME must be set")
+	}
+
+	f, err := libjvm.JVMFingerprint(javaHome)
+	if err != nil {
+		return false, "", fmt.Errorf("unable to fingerprint JVM\n%w", err)
+	}
+
+	return f == fingerprint, javaHome, nil
+}
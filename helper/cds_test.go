@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+	"github.com/paketo-buildpacks/libjvm/helper"
+)
+
+func testCDS(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		c = helper.CDS{}
+
+		archive  string
+		javaHome string
+	)
+
+	it.Before(func() {
+		javaHome = t.TempDir()
+		Expect(os.WriteFile(filepath.Join(javaHome, "release"), []byte(`JAVA_VERSION="17.0.9"`), 0644)).To(Succeed())
+		t.Setenv("JAVA_HOME", javaHome)
+
+		archive = filepath.Join(t.TempDir(), "application.jsa")
+		Expect(os.WriteFile(archive, []byte{}, 0644)).To(Succeed())
+	})
+
+	it("returns if $BPI_JVM_CDS_ARCHIVE is not set", func() {
+		Expect(c.Execute()).To(BeNil())
+	})
+
+	context("$BPI_JVM_CDS_ARCHIVE", func() {
+		it.Before(func() {
+			t.Setenv("BPI_JVM_CDS_ARCHIVE", archive)
+
+			f, err := libjvm.JVMFingerprint(javaHome)
+			Expect(err).NotTo(HaveOccurred())
+			t.Setenv("BPI_JVM_CDS_JVM_FINGERPRINT", f)
+		})
+
+		it("contributes shared archive file", func() {
+			Expect(c.Execute()).To(Equal(map[string]string{
+				"JAVA_TOOL_OPTIONS": "-XX:SharedArchiveFile=" + archive,
+			}))
+		})
+
+		it("appends to existing $JAVA_TOOL_OPTIONS", func() {
+			t.Setenv("JAVA_TOOL_OPTIONS", "test-java-tool-options")
+
+			Expect(c.Execute()).To(Equal(map[string]string{
+				"JAVA_TOOL_OPTIONS": "test-java-tool-options -XX:SharedArchiveFile=" + archive,
+			}))
+		})
+
+		it("does not override user configured archive", func() {
+			t.Setenv("JAVA_TOOL_OPTIONS", "-XX:SharedArchiveFile=/test/archive.jsa")
+
+			Expect(c.Execute()).To(BeNil())
+		})
+
+		it("disables CDS if the JVM has changed", func() {
+			Expect(os.WriteFile(filepath.Join(javaHome, "release"), []byte(`JAVA_VERSION="17.0.10"`), 0644)).To(Succeed())
+
+			Expect(c.Execute()).To(BeNil())
+		})
+
+		it("disables CDS if the archive is missing", func() {
+			Expect(os.Remove(archive)).To(Succeed())
+
+			Expect(c.Execute()).To(BeNil())
+		})
+	})
+}
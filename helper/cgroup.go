@@ -0,0 +1,166 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package helper
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	DefaultCgroupPath    = "/proc/self/cgroup"
+	DefaultMountInfoPath = "/proc/self/mountinfo"
+)
+
+// Cgroup is a memory cgroup that a process belongs to.
+type Cgroup struct {
+	// Version is the cgroup version, either 1 or 2.
+	Version int
+
+	// Path is the directory of the process's cgroup.
+	Path string
+
+	// MountPoint is the directory that the cgroup hierarchy is mounted at.
+	MountPoint string
+}
+
+// ResolveCgroups returns the memory cgroups of the process described by the cgroup file (usually /proc/self/cgroup),
+// located using the mount info file (usually /proc/self/mountinfo). On hybrid hosts both a cgroup v1 memory cgroup
+// and a cgroup v2 unified cgroup may be returned.
+func ResolveCgroups(cgroupPath string, mountInfoPath string) ([]Cgroup, error) {
+	paths, err := readProcessCgroups(cgroupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(mountInfoPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s\n%w", mountInfoPath, err)
+	}
+	defer file.Close()
+
+	var cgroups []Cgroup
+	s := bufio.NewScanner(file)
+	for s.Scan() {
+		f := strings.Fields(s.Text())
+
+		sep := -1
+		for i, v := range f {
+			if v == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 5 || len(f) < sep+4 {
+			continue
+		}
+
+		var version int
+		switch f[sep+1] {
+		case "cgroup2":
+			version = 2
+		case "cgroup":
+			if !containsOption(f[sep+3], "memory") {
+				continue
+			}
+			version = 1
+		default:
+			continue
+		}
+
+		p, ok := paths[version]
+		if !ok {
+			continue
+		}
+
+		root, mountPoint := f[3], f[4]
+		dir := mountPoint
+		if rel, err := filepath.Rel(root, p); err == nil && !strings.HasPrefix(rel, "..") {
+			dir = filepath.Join(mountPoint, rel)
+		}
+
+		cgroups = append(cgroups, Cgroup{Version: version, Path: dir, MountPoint: mountPoint})
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s\n%w", mountInfoPath, err)
+	}
+
+	return cgroups, nil
+}
+
+// Hierarchy returns the directories from the process's cgroup up to the root of the mounted hierarchy.
+func (c Cgroup) Hierarchy() []string {
+	var dirs []string
+
+	for d := c.Path; ; d = filepath.Dir(d) {
+		dirs = append(dirs, d)
+		if d == c.MountPoint || d == filepath.Dir(d) {
+			break
+		}
+	}
+
+	return dirs
+}
+
+// MemoryLimitFiles returns the files in a cgroup directory that limit memory usage.
+func (c Cgroup) MemoryLimitFiles() []string {
+	if c.Version == 1 {
+		return []string{"memory.limit_in_bytes"}
+	}
+	return []string{"memory.max", "memory.high"}
+}
+
+// readProcessCgroups returns the cgroup path of the process keyed by cgroup version. Only the memory controller is
+// considered for cgroup v1.
+func readProcessCgroups(cgroupPath string) (map[int]string, error) {
+	file, err := os.Open(cgroupPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s\n%w", cgroupPath, err)
+	}
+	defer file.Close()
+
+	paths := map[int]string{}
+	s := bufio.NewScanner(file)
+	for s.Scan() {
+		f := strings.SplitN(s.Text(), ":", 3)
+		if len(f) != 3 {
+			continue
+		}
+
+		if f[0] == "0" && f[1] == "" {
+			paths[2] = f[2]
+		} else if containsOption(f[1], "memory") {
+			paths[1] = f[2]
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s\n%w", cgroupPath, err)
+	}
+
+	return paths, nil
+}
+
+func containsOption(options string, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package helper_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm/helper"
+)
+
+func testCgroup(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = t.TempDir()
+	})
+
+	it("resolves cgroup v2", func() {
+		Expect(os.WriteFile(filepath.Join(path, "cgroup"), []byte("0::/kubepods/pod-1/container-1\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "mountinfo"), []byte(
+			"32 24 0:28 / /sys/fs/cgroup rw,nosuid - cgroup2 cgroup2 rw\n"), 0644)).To(Succeed())
+
+		Expect(helper.ResolveCgroups(filepath.Join(path, "cgroup"), filepath.Join(path, "mountinfo"))).To(Equal([]helper.Cgroup{
+			{Version: 2, Path: "/sys/fs/cgroup/kubepods/pod-1/container-1", MountPoint: "/sys/fs/cgroup"},
+		}))
+	})
+
+	it("resolves cgroup v1 memory controller on hybrid hosts", func() {
+		Expect(os.WriteFile(filepath.Join(path, "cgroup"), []byte("5:cpu,cpuacct:/\n4:memory:/docker/abc\n0::/\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "mountinfo"), []byte(
+			"33 32 0:29 / /sys/fs/cgroup/cpu,cpuacct rw,relatime - cgroup cgroup rw,cpu,cpuacct\n"+
+				"36 32 0:32 /docker/abc /sys/fs/cgroup/memory rw,relatime - cgroup cgroup rw,memory\n"+
+				"42 32 0:38 / /sys/fs/cgroup/unified rw,relatime - cgroup2 cgroup2 rw\n"), 0644)).To(Succeed())
+
+		Expect(helper.ResolveCgroups(filepath.Join(path, "cgroup"), filepath.Join(path, "mountinfo"))).To(Equal([]helper.Cgroup{
+			{Version: 1, Path: "/sys/fs/cgroup/memory", MountPoint: "/sys/fs/cgroup/memory"},
+			{Version: 2, Path: "/sys/fs/cgroup/unified", MountPoint: "/sys/fs/cgroup/unified"},
+		}))
+	})
+
+	it("returns error if cgroup file does not exist", func() {
+		_, err := helper.ResolveCgroups(filepath.Join(path, "cgroup"), filepath.Join(path, "mountinfo"))
+		Expect(err).To(MatchError(HavePrefix(fmt.Sprintf("unable to open %s", filepath.Join(path, "cgroup")))))
+	})
+
+	it("returns hierarchy", func() {
+		c := helper.Cgroup{Version: 2, Path: "/sys/fs/cgroup/a/b", MountPoint: "/sys/fs/cgroup"}
+		Expect(c.Hierarchy()).To(Equal([]string{"/sys/fs/cgroup/a/b", "/sys/fs/cgroup/a", "/sys/fs/cgroup"}))
+	})
+
+	it("returns memory limit files", func() {
+		Expect(helper.Cgroup{Version: 1}.MemoryLimitFiles()).To(Equal([]string{"memory.limit_in_bytes"}))
+		Expect(helper.Cgroup{Version: 2}.MemoryLimitFiles()).To(Equal([]string{"memory.max", "memory.high"}))
+	})
+}
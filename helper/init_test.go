@@ -26,6 +26,9 @@ import (
 func TestUnit(t *testing.T) {
 	suite := spec.New("libjvm/helper", spec.Report(report.Terminal{}))
 	suite("ActiveProcessorCount", testActiveProcessorCount)
+	suite("AOTCache", testAOTCache)
+	suite("CDS", testCDS)
+	suite("Cgroup", testCgroup)
 	suite("JavaOpts", testJavaOpts)
 	suite("JVMHeapDump", testJVMHeapDump)
 	suite("LinkLocalDNS", testLinkLocalDNS)
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/paketo-buildpacks/libpak/bard"
 
+	"github.com/paketo-buildpacks/libjvm"
 	"github.com/paketo-buildpacks/libjvm/calc"
 	"github.com/paketo-buildpacks/libjvm/count"
 )
@@ -48,6 +50,11 @@ type MemoryCalculator struct {
 	MemoryLimitPathV1 string
 	MemoryLimitPathV2 string
 	MemoryInfoPath    string
+
+	// CgroupPath and MountInfoPath locate the process's own cgroups, whose hierarchies are walked for memory limits.
+	// If CgroupPath is empty, only MemoryLimitPathV1 and MemoryLimitPathV2 are read.
+	CgroupPath    string
+	MountInfoPath string
 }
 
 func (m MemoryCalculator) Execute() (map[string]string, error) {
@@ -58,8 +65,17 @@ func (m MemoryCalculator) Execute() (map[string]string, error) {
 			ThreadCount: DefaultThreadCount,
 		}
 		deprecatedHeadroom bool
+		report             = MemoryCalculatorReport{
+			ClassCount: MemoryCalculatorReportClasses{Provenance: calc.UserConfigured},
+		}
 	)
 
+	reportFormat, reportEnabled := os.LookupEnv("BPL_JVM_MEMORY_CALCULATOR_REPORT")
+	if reportEnabled && reportFormat != MemoryCalculatorReportJSON && reportFormat != MemoryCalculatorReportText {
+		return nil, fmt.Errorf("unable to use $BPL_JVM_MEMORY_CALCULATOR_REPORT=%s, must be one of %s or %s",
+			reportFormat, MemoryCalculatorReportJSON, MemoryCalculatorReportText)
+	}
+
 	if s, ok := os.LookupEnv("BPL_JVM_HEADROOM"); ok {
 		if c.HeadRoom, err = strconv.Atoi(s); err != nil {
 			return nil, fmt.Errorf("unable to convert $BPL_JVM_HEADROOM=%s to integer\n%w", s, err)
@@ -77,6 +93,16 @@ func (m MemoryCalculator) Execute() (map[string]string, error) {
 		}
 	}
 
+	// the application is only fingerprinted once, and only if a count or estimate from build is used
+	var unchanged *bool
+	unchangedSinceBuild := func(appPath string) bool {
+		if unchanged == nil {
+			u := m.UnchangedSinceBuild(appPath)
+			unchanged = &u
+		}
+		return *unchanged
+	}
+
 	var values []string
 	opts, ok := os.LookupEnv("JAVA_TOOL_OPTIONS")
 	if ok {
@@ -121,26 +147,71 @@ func (m MemoryCalculator) Execute() (map[string]string, error) {
 			}
 		}
 
-		appClassCount, err := count.Classes(appPath)
-
-		totalClasses := float64(jvmClassCount+appClassCount+agentClassCount+staticAdjustment) * (float64(adjustmentFactor) / 100.0)
-
+		appClassCount, err := m.CountApplicationClasses(appPath, unchangedSinceBuild(appPath))
 		if err != nil {
 			return nil, fmt.Errorf("unable to determine class count\n%w", err)
 		}
+
+		totalClasses := float64(jvmClassCount+appClassCount+agentClassCount+staticAdjustment) * (float64(adjustmentFactor) / 100.0)
 		m.Logger.Debugf("Memory Calculation: (%d%% * (%d + %d + %d + %d)) * %0.2f", adjustmentFactor, jvmClassCount, appClassCount, agentClassCount, staticAdjustment, ClassLoadFactor)
 		c.LoadedClassCount = int(totalClasses * ClassLoadFactor)
+
+		report.ClassCount = MemoryCalculatorReportClasses{
+			Provenance:       calc.Calculated,
+			JVM:              jvmClassCount,
+			Application:      appClassCount,
+			Agent:            agentClassCount,
+			StaticAdjustment: staticAdjustment,
+			AdjustmentFactor: adjustmentFactor,
+			LoadFactor:       ClassLoadFactor,
+		}
+	}
+
+	if s, ok := os.LookupEnv("BPL_JVM_HEAP_PERCENTAGE"); ok {
+		if c.HeapPercentage, err = strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64); err != nil {
+			return nil, fmt.Errorf("unable to convert $BPL_JVM_HEAP_PERCENTAGE=%s to a number\n%w", s, err)
+		}
+		if c.HeapPercentage <= 0 || c.HeapPercentage > 100 {
+			return nil, fmt.Errorf("$BPL_JVM_HEAP_PERCENTAGE=%s must be greater than 0 and not greater than 100", s)
+		}
+	}
+
+	if s, ok := os.LookupEnv("BPL_JVM_MEMORY_PROFILE"); ok {
+		if c.Profile, err = calc.ParseProfile(s); err != nil {
+			return nil, fmt.Errorf("unable to use $BPL_JVM_MEMORY_PROFILE=%s\n%w", s, err)
+		}
 	}
 
 	if threadCount, ok := os.LookupEnv("BPL_JVM_THREAD_COUNT"); ok {
 		if c.ThreadCount, err = strconv.Atoi(threadCount); err != nil {
 			return nil, fmt.Errorf("unable to convert $BPL_JVM_THREAD_COUNT=%s to integer\n%w", threadCount, err)
 		}
+	} else if appPath, ok := os.LookupEnv("BPI_APPLICATION_PATH"); ok && ResolveBoolWithDefault("BPL_JVM_THREAD_COUNT_ESTIMATION", true) {
+		if e, err := m.EstimateThreadCount(appPath, unchangedSinceBuild(appPath)); err != nil {
+			m.Logger.Infof("WARNING: Unable to estimate thread count, using %d: %s", DefaultThreadCount, err)
+		} else if len(e.Evidence) > 0 {
+			m.Logger.Infof("Estimated thread count %d from %s", e.Count, strings.Join(e.Evidence, ", "))
+			c.ThreadCount = e.Count
+			report.ThreadCountEvidence = e.Evidence
+		}
 	}
 
-	totalMemory := m.getMemoryLimitFromPath(m.MemoryLimitPathV1)
-	if totalMemory == UnsetTotalMemory {
-		totalMemory = m.getMemoryLimitFromPath(m.MemoryLimitPathV2)
+	cgroups := m.resolveCgroups()
+
+	var limitSource string
+	totalMemory := UnsetTotalMemory
+	for _, p := range m.memoryLimitPaths(cgroups) {
+		if l := m.getMemoryLimitFromPath(p); l < totalMemory {
+			totalMemory, limitSource = l, p
+		}
+	}
+	if totalMemory != UnsetTotalMemory && len(cgroups) > 0 {
+		m.Logger.Infof("Calculating JVM memory based on %s memory limit from %s", calc.Size{Value: totalMemory}, limitSource)
+	}
+
+	swapLimit := m.getSwapLimit(cgroups)
+	if swapLimit != "" && swapLimit != "0" {
+		m.Logger.Infof("WARNING: Container allows swap (memory.swap.max %s), swap is not included in the JVM memory calculation", swapLimit)
 	}
 
 	if totalMemory == UnsetTotalMemory {
@@ -152,6 +223,7 @@ func (m MemoryCalculator) Execute() (map[string]string, error) {
 			} else {
 				m.Logger.Infof("Calculating JVM memory based on %s available memory", calc.Size{Value: mem}.String())
 				m.Logger.Info("For more information on this calculation, see https://paketo.io/docs/reference/java-reference/#memory-calculator")
+				limitSource = m.MemoryInfoPath
 				totalMemory = mem
 			}
 		}
@@ -159,6 +231,7 @@ func (m MemoryCalculator) Execute() (map[string]string, error) {
 
 	if totalMemory == UnsetTotalMemory {
 		m.Logger.Info("WARNING: Unable to determine memory limit. Configuring JVM for 1G container.")
+		limitSource = "default"
 		c.TotalMemory = calc.Size{Value: calc.Gibi}
 	} else if totalMemory > MaxJVMSize {
 		m.Logger.Info("WARNING: Container memory limit too large. Configuring JVM for 64T container.")
@@ -172,30 +245,106 @@ func (m MemoryCalculator) Execute() (map[string]string, error) {
 		return nil, fmt.Errorf("unable to calculate memory configuration\n%w", err)
 	}
 
-	var calculated []string
-	if r.DirectMemory.Provenance != calc.UserConfigured {
-		calculated = append(calculated, r.DirectMemory.String())
-	}
-	if r.Heap.Provenance != calc.UserConfigured {
-		calculated = append(calculated, r.Heap.String())
-	}
-	if r.Metaspace.Provenance != calc.UserConfigured {
-		calculated = append(calculated, r.Metaspace.String())
-	}
-	if r.ReservedCodeCache.Provenance != calc.UserConfigured {
-		calculated = append(calculated, r.ReservedCodeCache.String())
+	for _, w := range r.Warnings {
+		m.Logger.Infof("WARNING: %s", w)
 	}
-	if r.Stack.Provenance != calc.UserConfigured {
-		calculated = append(calculated, r.Stack.String())
+
+	calculated := r.Flags()
+
+	p, err := c.Profile.Flags(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply memory profile %s\n%w", c.Profile.Name, err)
 	}
+	calculated = append(calculated, p...)
+
 	values = append(values, calculated...)
 
 	m.Logger.Infof("Calculated JVM Memory Configuration: %s (Total Memory: %s, Thread Count: %d, Loaded Class Count: %d, Headroom: %d%%)",
 		strings.Join(calculated, " "), c.TotalMemory, c.ThreadCount, c.LoadedClassCount, c.HeadRoom)
+	if c.Profile.Name != "" {
+		m.Logger.Infof("Applied JVM memory profile %s", c.Profile.Name)
+	}
+
+	if reportEnabled {
+		report.TotalMemory = c.TotalMemory.Value
+		report.MemoryLimitSource = limitSource
+		report.SwapLimit = swapLimit
+		report.ThreadCount = c.ThreadCount
+		report.HeadRoom = c.HeadRoom
+		report.HeapPercentage = c.HeapPercentage
+		report.Profile = c.Profile.Name
+		report.ClassCount.Loaded = c.LoadedClassCount
+		report.Regions = NewMemoryCalculatorReportRegions(r.Regions())
+
+		if err := report.Write(reportFormat, os.Getenv("BPL_JVM_MEMORY_CALCULATOR_REPORT_PATH")); err != nil {
+			return nil, fmt.Errorf("unable to write memory calculator report\n%w", err)
+		}
+	}
 
 	return map[string]string{"JAVA_TOOL_OPTIONS": strings.Join(values, " ")}, nil
 }
 
+func (m MemoryCalculator) resolveCgroups() []Cgroup {
+	if m.CgroupPath == "" {
+		return nil
+	}
+
+	c, err := ResolveCgroups(m.CgroupPath, m.MountInfoPath)
+	if err != nil {
+		m.Logger.Infof("WARNING: Unable to resolve cgroups: %s", err)
+		return nil
+	}
+
+	return c
+}
+
+// memoryLimitPaths returns the configured memory limit paths followed by every memory limit file in the hierarchies of
+// the process's cgroups.
+func (m MemoryCalculator) memoryLimitPaths(cgroups []Cgroup) []string {
+	paths := []string{m.MemoryLimitPathV1, m.MemoryLimitPathV2}
+
+	for _, c := range cgroups {
+		for _, d := range c.Hierarchy() {
+			for _, f := range c.MemoryLimitFiles() {
+				paths = append(paths, filepath.Join(d, f))
+			}
+		}
+	}
+
+	return paths
+}
+
+// getSwapLimit returns the lowest memory.swap.max in the hierarchies of cgroup v2 cgroups, "max" if swap is unlimited
+// or empty if no limit could be read.
+func (m MemoryCalculator) getSwapLimit(cgroups []Cgroup) string {
+	var swap string
+	limit := UnsetTotalMemory
+
+	for _, c := range cgroups {
+		if c.Version != 2 {
+			continue
+		}
+
+		for _, d := range c.Hierarchy() {
+			b, err := os.ReadFile(filepath.Join(d, "memory.swap.max"))
+			if err != nil {
+				continue
+			}
+
+			v := strings.TrimSpace(string(b))
+			if v == "max" {
+				if swap == "" {
+					swap = v
+				}
+			} else if size, err := calc.ParseSize(v); err == nil && size.Value < limit {
+				limit, swap = size.Value, v
+			}
+		}
+	}
+
+	return swap
+}
+
 func (m MemoryCalculator) getMemoryLimitFromPath(memoryLimitPath string) int64 {
 	if b, err := ioutil.ReadFile(memoryLimitPath); err != nil && !os.IsNotExist(err) {
 		m.Logger.Infof("WARNING: Unable to read %s: %s", memoryLimitPath, err)
@@ -232,6 +381,73 @@ func parseMemInfo(s string) (int64, error) {
 	return num * unit, nil
 }
 
+// UnchangedSinceBuild returns whether the application is unchanged since the class count and thread count estimate
+// were made at build time, so that they can be used instead of walking the application.
+func (m MemoryCalculator) UnchangedSinceBuild(appPath string) bool {
+	expected, ok := os.LookupEnv("BPI_APPLICATION_FINGERPRINT")
+	if !ok {
+		return false
+	}
+
+	f, err := count.Fingerprint(appPath)
+	if err != nil {
+		m.Logger.Infof("WARNING: Unable to fingerprint application, counting classes and estimating threads: %s", err)
+		return false
+	}
+
+	if f != expected {
+		m.Logger.Debug("Application has changed since build, counting classes and estimating threads")
+		return false
+	}
+
+	return true
+}
+
+// CountApplicationClasses returns the number of classes in the application, reusing the count made at build time if
+// the application is unchanged since.
+func (m MemoryCalculator) CountApplicationClasses(appPath string, unchangedSinceBuild bool) (int, error) {
+	if s, ok := os.LookupEnv("BPI_APPLICATION_CLASS_COUNT"); ok && unchangedSinceBuild {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("unable to convert $BPI_APPLICATION_CLASS_COUNT=%s to integer\n%w", s, err)
+		}
+
+		m.Logger.Debugf("Using application class count %d from build", c)
+		return c, nil
+	}
+
+	c, skipped, err := count.ClassesAndSkippedJars(appPath)
+	if err != nil {
+		return 0, err
+	}
+	if skipped > 0 {
+		m.Logger.Infof("WARNING: Skipped %d compressed nested JARs too large to count, the class count may be too low", skipped)
+	}
+
+	return c, nil
+}
+
+// EstimateThreadCount returns the number of threads the application will use, reusing the estimate made at build time
+// if the application is unchanged since.
+func (m MemoryCalculator) EstimateThreadCount(appPath string, unchangedSinceBuild bool) (libjvm.ThreadCountEstimate, error) {
+	if s, ok := os.LookupEnv("BPI_APPLICATION_THREAD_COUNT"); ok && unchangedSinceBuild {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return libjvm.ThreadCountEstimate{}, fmt.Errorf("unable to convert $BPI_APPLICATION_THREAD_COUNT=%s to integer\n%w", s, err)
+		}
+
+		e := libjvm.ThreadCountEstimate{Count: c}
+		if s := os.Getenv("BPI_APPLICATION_THREAD_COUNT_EVIDENCE"); s != "" {
+			e.Evidence = strings.Split(s, libjvm.ThreadCountEvidenceSeparator)
+		}
+
+		m.Logger.Debugf("Using application thread count estimate %d from build", c)
+		return e, nil
+	}
+
+	return libjvm.EstimateThreadCount(appPath)
+}
+
 func (m MemoryCalculator) CountAgentClasses(opts string) (int, error) {
 	var agentClassCount, skippedAgents int
 	if p, err := shellwords.Parse(opts); err != nil {
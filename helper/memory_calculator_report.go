@@ -0,0 +1,156 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/paketo-buildpacks/libjvm/calc"
+)
+
+const (
+	MemoryCalculatorReportJSON = "json"
+	MemoryCalculatorReportText = "text"
+)
+
+// MemoryCalculatorReport is a machine-readable breakdown of a memory calculation.
+type MemoryCalculatorReport struct {
+	TotalMemory         int64                          `json:"totalMemory"`
+	MemoryLimitSource   string                         `json:"memoryLimitSource"`
+	SwapLimit           string                         `json:"swapLimit,omitempty"`
+	ThreadCount         int                            `json:"threadCount"`
+	ThreadCountEvidence []string                       `json:"threadCountEvidence,omitempty"`
+	HeadRoom            int                            `json:"headRoom"`
+	HeapPercentage      float64                        `json:"heapPercentage,omitempty"`
+	Profile             string                         `json:"profile,omitempty"`
+	ClassCount          MemoryCalculatorReportClasses  `json:"classCount"`
+	Regions             []MemoryCalculatorReportRegion `json:"regions"`
+}
+
+// MemoryCalculatorReportClasses describes the inputs to the loaded class count.
+type MemoryCalculatorReportClasses struct {
+	Loaded           int             `json:"loaded"`
+	Provenance       calc.Provenance `json:"provenance"`
+	JVM              int             `json:"jvm,omitempty"`
+	Application      int             `json:"application,omitempty"`
+	Agent            int             `json:"agent,omitempty"`
+	StaticAdjustment int             `json:"staticAdjustment,omitempty"`
+	AdjustmentFactor uint64          `json:"adjustmentFactor,omitempty"`
+	LoadFactor       float64         `json:"loadFactor,omitempty"`
+}
+
+// MemoryCalculatorReportRegion describes a single calculated memory region.
+type MemoryCalculatorReportRegion struct {
+	Name       string          `json:"name"`
+	Flag       string          `json:"flag,omitempty"`
+	Value      int64           `json:"value"`
+	Provenance calc.Provenance `json:"provenance"`
+}
+
+func NewMemoryCalculatorReportRegions(regions []calc.Region) []MemoryCalculatorReportRegion {
+	var r []MemoryCalculatorReportRegion
+	for _, g := range regions {
+		r = append(r, MemoryCalculatorReportRegion{
+			Name:       g.Name,
+			Flag:       g.Flag,
+			Value:      g.Size.Value,
+			Provenance: g.Size.Provenance,
+		})
+	}
+	return r
+}
+
+// Write writes the report to path, or to stdout if path is empty, in the given format.
+func (r MemoryCalculatorReport) Write(format string, path string) error {
+	var w io.Writer = os.Stdout
+
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("unable to open %s\n%w", path, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return r.WriteFormat(format, w)
+}
+
+// WriteFormat writes the report to w in format.
+func (r MemoryCalculatorReport) WriteFormat(format string, w io.Writer) error {
+	switch format {
+	case MemoryCalculatorReportJSON:
+		return r.WriteJSON(w)
+	case MemoryCalculatorReportText:
+		return r.WriteText(w)
+	default:
+		return fmt.Errorf("unknown report format %q, must be one of %s or %s",
+			format, MemoryCalculatorReportJSON, MemoryCalculatorReportText)
+	}
+}
+
+func (r MemoryCalculatorReport) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	if err := e.Encode(r); err != nil {
+		return fmt.Errorf("unable to encode report\n%w", err)
+	}
+	return nil
+}
+
+func (r MemoryCalculatorReport) WriteText(w io.Writer) error {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(t, "JVM Memory Configuration Report")
+	fmt.Fprintf(t, "  Total Memory:\t%s\t(%s)\n", calc.Size{Value: r.TotalMemory}, r.MemoryLimitSource)
+	if r.SwapLimit != "" {
+		fmt.Fprintf(t, "  Swap Limit:\t%s\n", r.SwapLimit)
+	}
+	fmt.Fprintf(t, "  Thread Count:\t%d\n", r.ThreadCount)
+	for _, e := range r.ThreadCountEvidence {
+		fmt.Fprintf(t, "    Evidence:\t%s\n", e)
+	}
+	fmt.Fprintf(t, "  Head Room:\t%d%%\n", r.HeadRoom)
+	if r.HeapPercentage > 0 {
+		fmt.Fprintf(t, "  Heap Percentage:\t%g%%\n", r.HeapPercentage)
+	}
+	if r.Profile != "" {
+		fmt.Fprintf(t, "  Profile:\t%s\n", r.Profile)
+	}
+	fmt.Fprintf(t, "  Loaded Class Count:\t%d\t(%s)\n", r.ClassCount.Loaded, r.ClassCount.Provenance)
+	if r.ClassCount.Provenance == calc.Calculated {
+		fmt.Fprintf(t, "    JVM Classes:\t%d\n", r.ClassCount.JVM)
+		fmt.Fprintf(t, "    Application Classes:\t%d\n", r.ClassCount.Application)
+		fmt.Fprintf(t, "    Agent Classes:\t%d\n", r.ClassCount.Agent)
+		fmt.Fprintf(t, "    Static Adjustment:\t%d\n", r.ClassCount.StaticAdjustment)
+		fmt.Fprintf(t, "    Adjustment Factor:\t%d%%\n", r.ClassCount.AdjustmentFactor)
+		fmt.Fprintf(t, "    Load Factor:\t%0.2f\n", r.ClassCount.LoadFactor)
+	}
+	fmt.Fprintln(t, "  Regions:")
+	for _, g := range r.Regions {
+		fmt.Fprintf(t, "    %s:\t%s\t%s\t(%s)\n", g.Name, calc.Size{Value: g.Value}, g.Flag, g.Provenance)
+	}
+
+	if err := t.Flush(); err != nil {
+		return fmt.Errorf("unable to write report\n%w", err)
+	}
+	return nil
+}
@@ -17,6 +17,7 @@
 package helper_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -27,7 +28,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/sclevine/spec"
 
+	"github.com/paketo-buildpacks/libjvm"
 	"github.com/paketo-buildpacks/libjvm/calc"
+	"github.com/paketo-buildpacks/libjvm/count"
 	"github.com/paketo-buildpacks/libjvm/helper"
 )
 
@@ -115,6 +118,34 @@ func testMemoryCalculator(t *testing.T, context spec.G, it spec.S) {
 				}))
 			})
 
+			context("$BPI_APPLICATION_CLASS_COUNT", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BPI_APPLICATION_CLASS_COUNT", "1000")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BPI_APPLICATION_CLASS_COUNT")).To(Succeed())
+					Expect(os.Unsetenv("BPI_APPLICATION_FINGERPRINT")).To(Succeed())
+				})
+
+				it("uses class count from build if application is unchanged", func() {
+					f, err := count.Fingerprint(applicationPath)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(os.Setenv("BPI_APPLICATION_FINGERPRINT", f)).To(Succeed())
+
+					Expect(m.CountApplicationClasses(applicationPath, m.UnchangedSinceBuild(applicationPath))).To(Equal(1000))
+				})
+
+				it("counts classes if application has changed", func() {
+					Expect(os.Setenv("BPI_APPLICATION_FINGERPRINT", "changed")).To(Succeed())
+
+					Expect(m.CountApplicationClasses(applicationPath, m.UnchangedSinceBuild(applicationPath))).To(Equal(0))
+					Expect(m.Execute()).To(Equal(map[string]string{
+						"JAVA_TOOL_OPTIONS": "-XX:MaxDirectMemorySize=10M -Xmx522705K -XX:MaxMetaspaceSize=13870K -XX:ReservedCodeCacheSize=240M -Xss1M",
+					}))
+				})
+			})
+
 			context("$BPL_JVM_CLASS_ADJUSTMENT", func() {
 				context("set to a static number", func() {
 					context("positive number", func() {
@@ -267,6 +298,113 @@ func testMemoryCalculator(t *testing.T, context spec.G, it spec.S) {
 				})
 			})
 
+			context("$BPL_JVM_HEAP_PERCENTAGE", func() {
+				it.After(func() {
+					Expect(os.Unsetenv("BPL_JVM_HEAP_PERCENTAGE")).To(Succeed())
+				})
+
+				it("passes $BPL_JVM_HEAP_PERCENTAGE to calculator", func() {
+					Expect(os.Setenv("BPL_JVM_HEAP_PERCENTAGE", "25")).To(Succeed())
+
+					Expect(m.Execute()).To(Equal(map[string]string{
+						"JAVA_TOOL_OPTIONS": "-XX:MaxDirectMemorySize=10M -Xmx256M -XX:MaxMetaspaceSize=13870K -XX:ReservedCodeCacheSize=240M -Xss1M",
+					}))
+				})
+
+				it("returns error if out of range", func() {
+					Expect(os.Setenv("BPL_JVM_HEAP_PERCENTAGE", "0")).To(Succeed())
+
+					_, err := m.Execute()
+					Expect(err).To(MatchError("$BPL_JVM_HEAP_PERCENTAGE=0 must be greater than 0 and not greater than 100"))
+				})
+			})
+
+			context("$BPL_JVM_MEMORY_PROFILE", func() {
+				it.After(func() {
+					Expect(os.Unsetenv("BPL_JVM_MEMORY_PROFILE")).To(Succeed())
+				})
+
+				it("applies latency profile", func() {
+					Expect(os.Setenv("BPL_JVM_MEMORY_PROFILE", "latency")).To(Succeed())
+
+					Expect(m.Execute()).To(Equal(map[string]string{
+						"JAVA_TOOL_OPTIONS": "-XX:MaxDirectMemorySize=10M -Xmx522705K -Xms522705K -XX:MaxMetaspaceSize=13870K -XX:ReservedCodeCacheSize=240M -Xss1M -XX:+AlwaysPreTouch",
+					}))
+				})
+
+				it("applies batch profile", func() {
+					Expect(os.Setenv("BPL_JVM_MEMORY_PROFILE", "batch")).To(Succeed())
+
+					Expect(m.Execute()).To(Equal(map[string]string{
+						"JAVA_TOOL_OPTIONS": "-XX:MaxDirectMemorySize=4M -Xmx528849K -XX:MaxMetaspaceSize=13870K -XX:ReservedCodeCacheSize=240M -Xss1M",
+					}))
+				})
+
+				it("returns error for unknown profile", func() {
+					Expect(os.Setenv("BPL_JVM_MEMORY_PROFILE", "fast")).To(Succeed())
+
+					_, err := m.Execute()
+					Expect(err).To(MatchError(HavePrefix("unable to use $BPL_JVM_MEMORY_PROFILE=fast")))
+				})
+			})
+
+			context("thread count estimation", func() {
+				it.Before(func() {
+					Expect(os.MkdirAll(filepath.Join(applicationPath, "BOOT-INF", "lib"), 0755)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(applicationPath, "BOOT-INF", "lib", "spring-webflux-6.1.0.jar"), []byte{}, 0644)).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BPL_JVM_THREAD_COUNT_ESTIMATION")).To(Succeed())
+				})
+
+				it("estimates thread count from the application", func() {
+					Expect(m.Execute()).To(Equal(map[string]string{
+						"JAVA_TOOL_OPTIONS": "-XX:MaxDirectMemorySize=10M -Xmx676305K -XX:MaxMetaspaceSize=13870K -XX:ReservedCodeCacheSize=240M -Xss1M",
+					}))
+				})
+
+				context("$BPI_APPLICATION_THREAD_COUNT", func() {
+					it.Before(func() {
+						Expect(os.Setenv("BPI_APPLICATION_THREAD_COUNT", "300")).To(Succeed())
+						Expect(os.Setenv("BPI_APPLICATION_THREAD_COUNT_EVIDENCE", "Start-Class test.App\ntomcat-embed-core-10.1.0.jar")).To(Succeed())
+					})
+
+					it.After(func() {
+						Expect(os.Unsetenv("BPI_APPLICATION_THREAD_COUNT")).To(Succeed())
+						Expect(os.Unsetenv("BPI_APPLICATION_THREAD_COUNT_EVIDENCE")).To(Succeed())
+						Expect(os.Unsetenv("BPI_APPLICATION_FINGERPRINT")).To(Succeed())
+					})
+
+					it("uses thread count estimate from build if application is unchanged", func() {
+						f, err := count.Fingerprint(applicationPath)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(os.Setenv("BPI_APPLICATION_FINGERPRINT", f)).To(Succeed())
+
+						Expect(m.EstimateThreadCount(applicationPath, m.UnchangedSinceBuild(applicationPath))).To(Equal(libjvm.ThreadCountEstimate{
+							Count:    300,
+							Evidence: []string{"Start-Class test.App", "tomcat-embed-core-10.1.0.jar"},
+						}))
+					})
+
+					it("estimates thread count if application has changed", func() {
+						Expect(os.Setenv("BPI_APPLICATION_FINGERPRINT", "changed")).To(Succeed())
+
+						e, err := m.EstimateThreadCount(applicationPath, m.UnchangedSinceBuild(applicationPath))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(e.Count).To(Equal(libjvm.BaseThreadCount + libjvm.ReactiveThreadCount))
+					})
+				})
+
+				it("does not estimate thread count if disabled", func() {
+					Expect(os.Setenv("BPL_JVM_THREAD_COUNT_ESTIMATION", "false")).To(Succeed())
+
+					Expect(m.Execute()).To(Equal(map[string]string{
+						"JAVA_TOOL_OPTIONS": "-XX:MaxDirectMemorySize=10M -Xmx522705K -XX:MaxMetaspaceSize=13870K -XX:ReservedCodeCacheSize=240M -Xss1M",
+					}))
+				})
+			})
+
 			context("$BPL_JVM_THREAD_COUNT", func() {
 				it.Before(func() {
 					Expect(os.Setenv("BPL_JVM_THREAD_COUNT", "100")).To(Succeed())
@@ -347,6 +485,66 @@ func testMemoryCalculator(t *testing.T, context spec.G, it spec.S) {
 				}))
 			})
 
+			context("cgroup hierarchy", func() {
+				var cgroupRoot string
+
+				it.Before(func() {
+					cgroupRoot = filepath.Join(applicationPath, "sys", "fs", "cgroup")
+					Expect(os.MkdirAll(filepath.Join(cgroupRoot, "pod", "container"), 0755)).To(Succeed())
+
+					Expect(ioutil.WriteFile(filepath.Join(applicationPath, "cgroup"), []byte("0::/pod/container\n"), 0644)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(applicationPath, "mountinfo"),
+						[]byte(fmt.Sprintf("32 24 0:28 / %s rw - cgroup2 cgroup2 rw\n", cgroupRoot)), 0644)).To(Succeed())
+
+					m.CgroupPath = filepath.Join(applicationPath, "cgroup")
+					m.MountInfoPath = filepath.Join(applicationPath, "mountinfo")
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BPL_JVM_MEMORY_CALCULATOR_REPORT")).To(Succeed())
+					Expect(os.Unsetenv("BPL_JVM_MEMORY_CALCULATOR_REPORT_PATH")).To(Succeed())
+				})
+
+				it("uses the lowest limit in the hierarchy", func() {
+					Expect(ioutil.WriteFile(filepath.Join(cgroupRoot, "pod", "container", "memory.max"), []byte("max\n"), 0644)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(cgroupRoot, "pod", "memory.max"), strconv.AppendInt([]byte{}, 10*calc.Gibi, 10), 0644)).To(Succeed())
+
+					Expect(m.Execute()).To(Equal(map[string]string{
+						"JAVA_TOOL_OPTIONS": "-XX:MaxDirectMemorySize=10M -Xmx9959889K -XX:MaxMetaspaceSize=13870K -XX:ReservedCodeCacheSize=240M -Xss1M",
+					}))
+				})
+
+				it("uses memory.high if lower than memory.max", func() {
+					Expect(ioutil.WriteFile(filepath.Join(cgroupRoot, "pod", "container", "memory.max"), strconv.AppendInt([]byte{}, 11*calc.Gibi, 10), 0644)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(cgroupRoot, "pod", "container", "memory.high"), strconv.AppendInt([]byte{}, 10*calc.Gibi, 10), 0644)).To(Succeed())
+
+					Expect(m.Execute()).To(Equal(map[string]string{
+						"JAVA_TOOL_OPTIONS": "-XX:MaxDirectMemorySize=10M -Xmx9959889K -XX:MaxMetaspaceSize=13870K -XX:ReservedCodeCacheSize=240M -Xss1M",
+					}))
+				})
+
+				it("reports the winning file and swap limit", func() {
+					reportPath := filepath.Join(applicationPath, "report")
+					Expect(os.Setenv("BPL_JVM_MEMORY_CALCULATOR_REPORT", "json")).To(Succeed())
+					Expect(os.Setenv("BPL_JVM_MEMORY_CALCULATOR_REPORT_PATH", reportPath)).To(Succeed())
+
+					Expect(ioutil.WriteFile(filepath.Join(cgroupRoot, "pod", "memory.max"), strconv.AppendInt([]byte{}, 10*calc.Gibi, 10), 0644)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(cgroupRoot, "pod", "container", "memory.swap.max"), []byte("max\n"), 0644)).To(Succeed())
+					Expect(ioutil.WriteFile(filepath.Join(cgroupRoot, "pod", "memory.swap.max"), []byte("1073741824\n"), 0644)).To(Succeed())
+
+					_, err := m.Execute()
+					Expect(err).NotTo(HaveOccurred())
+
+					b, err := ioutil.ReadFile(reportPath)
+					Expect(err).NotTo(HaveOccurred())
+
+					var r helper.MemoryCalculatorReport
+					Expect(json.Unmarshal(b, &r)).To(Succeed())
+					Expect(r.MemoryLimitSource).To(Equal(filepath.Join(cgroupRoot, "pod", "memory.max")))
+					Expect(r.SwapLimit).To(Equal("1073741824"))
+				})
+			})
+
 			context("$JAVA_TOOL_OPTIONS", func() {
 				it.Before(func() {
 					Expect(os.Setenv("JAVA_TOOL_OPTIONS", "test-java-tool-options")).To(Succeed())
@@ -412,6 +610,62 @@ func testMemoryCalculator(t *testing.T, context spec.G, it spec.S) {
 				})
 			})
 
+			context("$BPL_JVM_MEMORY_CALCULATOR_REPORT", func() {
+				var reportPath string
+
+				it.Before(func() {
+					reportPath = filepath.Join(applicationPath, "report")
+					Expect(os.Setenv("BPL_JVM_MEMORY_CALCULATOR_REPORT_PATH", reportPath)).To(Succeed())
+					Expect(ioutil.WriteFile(memoryLimitPathV2, strconv.AppendInt([]byte{}, calc.Gibi, 10), 0755)).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BPL_JVM_MEMORY_CALCULATOR_REPORT")).To(Succeed())
+					Expect(os.Unsetenv("BPL_JVM_MEMORY_CALCULATOR_REPORT_PATH")).To(Succeed())
+				})
+
+				it("writes a JSON report", func() {
+					Expect(os.Setenv("BPL_JVM_MEMORY_CALCULATOR_REPORT", "json")).To(Succeed())
+
+					_, err := m.Execute()
+					Expect(err).NotTo(HaveOccurred())
+
+					b, err := ioutil.ReadFile(reportPath)
+					Expect(err).NotTo(HaveOccurred())
+
+					var r helper.MemoryCalculatorReport
+					Expect(json.Unmarshal(b, &r)).To(Succeed())
+					Expect(r.TotalMemory).To(Equal(calc.Gibi))
+					Expect(r.MemoryLimitSource).To(Equal(memoryLimitPathV2))
+					Expect(r.ThreadCount).To(Equal(250))
+					Expect(r.ClassCount.JVM).To(Equal(100))
+					Expect(r.ClassCount.Loaded).To(Equal(35))
+					Expect(r.Regions).To(ContainElement(helper.MemoryCalculatorReportRegion{
+						Name: "heap", Flag: "-Xmx522705K", Value: 535250824, Provenance: calc.Calculated,
+					}))
+					Expect(string(b)).To(ContainSubstring(`"provenance": "calculated"`))
+				})
+
+				it("writes a text report", func() {
+					Expect(os.Setenv("BPL_JVM_MEMORY_CALCULATOR_REPORT", "text")).To(Succeed())
+
+					_, err := m.Execute()
+					Expect(err).NotTo(HaveOccurred())
+
+					b, err := ioutil.ReadFile(reportPath)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(b)).To(ContainSubstring("JVM Memory Configuration Report"))
+					Expect(string(b)).To(MatchRegexp(`heap:\s+522705K\s+-Xmx522705K\s+\(calculated\)`))
+				})
+
+				it("returns error for unknown format", func() {
+					Expect(os.Setenv("BPL_JVM_MEMORY_CALCULATOR_REPORT", "xml")).To(Succeed())
+
+					_, err := m.Execute()
+					Expect(err).To(MatchError("unable to use $BPL_JVM_MEMORY_CALCULATOR_REPORT=xml, must be one of json or text"))
+				})
+			})
+
 			context("user configured", func() {
 				it.Before(func() {
 					Expect(os.Setenv("JAVA_TOOL_OPTIONS", "-XX:MaxDirectMemorySize=10M -Xmx522705K -XX:MaxMetaspaceSize=13870K -XX:ReservedCodeCacheSize=240M -Xss1M")).To(Succeed())
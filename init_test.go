@@ -31,19 +31,30 @@ var (
 
 func TestUnit(t *testing.T) {
 	suite := spec.New("libjvm", spec.Report(report.Terminal{}))
+	suite("AOTCache", testAOTCache)
+	suite("ApplicationClassCount", testApplicationClassCount)
 	suite("Build", testBuild)
+	suite("CDS", testCDS)
 	suite("CertificateLoader", testCertificateLoader)
+	suite("ClassVersion", testClassVersion)
 	suite("Contributions", testContributions)
 	suite("Detect", testDetect)
+	suite("JARJavaVersion", testJARJavaVersion)
+	suite("JARManifest", testJARManifest)
 	suite("JavaSecurityProperties", testJavaSecurityProperties)
+	suite("JavaVersionFallback", testJavaVersionFallback)
+	suite("JavaVersionSources", testJavaVersionSources)
 	suite("JDK", testJDK)
 	suite("JRE", testJRE)
 	suite("NIK", testNIK)
 	suite("JLink", testJLink)
+	suite("JLinkProfile", testJLinkProfile)
 	suite("NewManifest", testNewManifest)
 	suite("NewManifestFromJAR", testNewManifestFromJAR)
 	suite("MavenJARListing", testMavenJARListing)
 	suite("SDKMAN", testSDKMAN)
+	suite("ThreadCount", testThreadCount)
+	suite("TrainingRun", testTrainingRun)
 	suite("Versions", testVersions)
 	suite("JVMVersions", testJVMVersion)
 	suite("Keystore", testKeystore)
@@ -0,0 +1,233 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/magiconair/properties"
+
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+// JARJavaVersion is a Java version declared by the manifest of an application or one of its JARs.
+type JARJavaVersion struct {
+	// Attribute is the manifest attribute that declared the version.
+	Attribute string
+
+	// Path is the JAR that declared the version, relative to the application.  A nested JAR follows the JAR containing
+	// it, separated by !/, and the application's own manifest is META-INF/MANIFEST.MF.
+	Path string
+
+	// Version is the Java version.
+	Version string
+}
+
+// JavaVersionAttributes are the manifest attributes that declare the Java version a JAR was built with, in order of
+// precedence.
+var JavaVersionAttributes = []string{"Build-Jdk-Spec", "Build-Jdk", "Created-By"}
+
+// NestedLibraryDirectories are the directories of JARs nested in Spring Boot and WAR archives.
+var NestedLibraryDirectories = []string{"BOOT-INF/lib/", "WEB-INF/lib/"}
+
+// IgnoredJARDirectories are the directories of an application whose JARs are not part of it, such as build tool
+// wrappers.  Hidden directories are also ignored.
+var IgnoredJARDirectories = []string{"gradle/wrapper"}
+
+// createdByVersion matches a JDK version in Created-By, such as 17.0.6 (Eclipse Adoptium), but not the build tools that
+// also set it, such as Apache Maven 3.9.6.
+var createdByVersion = regexp.MustCompile(`^(\d+(?:[._]\d+)*)\S*(?:\s+\(.*\))?$`)
+
+// ReadJavaVersionFromJARs returns the Java version declared by the application's own manifest.  If it declares none,
+// the highest version declared by the JARs in the application, and the JARs nested in their library directories, is
+// returned instead.  As JARs are often built with a newer Java version than they target, a JAR's version is only used
+// if its classes are compiled for it.  Multi-release JARs are ignored, as they are built with a newer Java version than
+// they require.  An empty version is returned if no manifest declares one.
+func ReadJavaVersionFromJARs(appPath string) (JARJavaVersion, error) {
+	if _, err := os.Stat(appPath); os.IsNotExist(err) {
+		return JARJavaVersion{}, nil
+	}
+
+	manifest, err := NewManifest(appPath)
+	if err != nil {
+		return JARJavaVersion{}, fmt.Errorf("unable to read manifest\n%w", err)
+	}
+	if attribute, version, ok := manifestJavaVersion(manifest); ok {
+		if _, err := semver.NewVersion(NormalizeJavaVersion(version)); err == nil {
+			return JARJavaVersion{Attribute: attribute, Path: "META-INF/MANIFEST.MF", Version: version}, nil
+		}
+	}
+
+	var (
+		highest        JARJavaVersion
+		highestVersion *semver.Version
+	)
+
+	consider := func(r io.ReaderAt, z *zip.Reader, name string, path string) error {
+		m, err := newManifestFromZip(z, name)
+		if err != nil {
+			return err
+		}
+
+		attribute, version, ok := manifestJavaVersion(m)
+		if !ok {
+			return nil
+		}
+
+		v, err := semver.NewVersion(NormalizeJavaVersion(version))
+		if err != nil || (highestVersion != nil && !v.GreaterThan(highestVersion)) {
+			return nil
+		}
+
+		major, err := archiveMajorVersion(r, z, false)
+		if err != nil {
+			return fmt.Errorf("unable to read class versions in %s\n%w", name, err)
+		}
+		if major == 0 || JavaVersionForClassMajorVersion(major) != strconv.FormatUint(v.Major(), 10) {
+			return nil
+		}
+
+		highest, highestVersion = JARJavaVersion{Attribute: attribute, Path: path, Version: version}, v
+		return nil
+	}
+
+	if err := filepath.Walk(appPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(appPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if ignoredJARDirectory(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".jar") {
+			return nil
+		}
+
+		jar, z, err := openJAR(path)
+		if err != nil {
+			return err
+		} else if z == nil {
+			return nil
+		}
+		defer jar.Close()
+
+		if err := consider(jar, z, path, rel); err != nil {
+			return err
+		}
+
+		for _, f := range z.File {
+			if !nestedLibrary(f) {
+				continue
+			}
+
+			nr, nz, err := count.OpenNestedJar(jar, f)
+			if errors.Is(err, count.ErrNestedJarTooLarge) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("unable to open %s in %s\n%w", f.Name, path, err)
+			} else if nz == nil {
+				continue
+			}
+
+			if err := consider(nr, nz, fmt.Sprintf("%s!/%s", path, f.Name), fmt.Sprintf("%s!/%s", rel, f.Name)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return JARJavaVersion{}, fmt.Errorf("unable to walk %s\n%w", appPath, err)
+	}
+
+	return highest, nil
+}
+
+func ignoredJARDirectory(rel string) bool {
+	if rel != "." && strings.HasPrefix(filepath.Base(rel), ".") {
+		return true
+	}
+
+	for _, d := range IgnoredJARDirectories {
+		if rel == d {
+			return true
+		}
+	}
+
+	return false
+}
+
+// manifestJavaVersion returns the attribute that declares the Java version in a manifest, and the version.
+func manifestJavaVersion(manifest *properties.Properties) (string, string, bool) {
+	if r, ok := manifest.Get("Multi-Release"); ok && strings.EqualFold(strings.TrimSpace(r), "true") {
+		return "", "", false
+	}
+
+	for _, a := range JavaVersionAttributes {
+		v, ok := manifest.Get(a)
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+
+		if a == "Created-By" {
+			m := createdByVersion.FindStringSubmatch(v)
+			if m == nil {
+				continue
+			}
+			v = m[1]
+		}
+
+		if v != "" {
+			return a, v, true
+		}
+	}
+
+	return "", "", false
+}
+
+func nestedLibrary(f *zip.File) bool {
+	if !strings.HasSuffix(f.Name, ".jar") {
+		return false
+	}
+
+	for _, d := range NestedLibraryDirectories {
+		if strings.HasPrefix(f.Name, d) {
+			return true
+		}
+	}
+
+	return false
+}
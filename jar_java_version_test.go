@@ -0,0 +1,156 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testJARJavaVersion(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		appPath = t.TempDir()
+	})
+
+	archive := func(method uint16, files map[string][]byte) []byte {
+		b := &bytes.Buffer{}
+		z := zip.NewWriter(b)
+		for n, c := range files {
+			w, err := z.CreateHeader(&zip.FileHeader{Name: n, Method: method})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write(c)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(z.Close()).To(Succeed())
+		return b.Bytes()
+	}
+
+	jar := func(files map[string][]byte) []byte {
+		return archive(zip.Deflate, files)
+	}
+
+	manifest := func(content string) map[string][]byte {
+		return map[string][]byte{"META-INF/MANIFEST.MF": []byte(content)}
+	}
+
+	withClass := func(files map[string][]byte, major byte) map[string][]byte {
+		files["test/Alpha.class"] = []byte{0xCA, 0xFE, 0xBA, 0xBE, 0x00, 0x00, 0x00, major}
+		return files
+	}
+
+	write := func(file string, content []byte) {
+		Expect(os.MkdirAll(filepath.Dir(filepath.Join(appPath, file)), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(appPath, file), content, 0644)).To(Succeed())
+	}
+
+	it("returns nothing without manifests", func() {
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{}))
+		Expect(libjvm.ReadJavaVersionFromJARs(filepath.Join(appPath, "missing"))).To(Equal(libjvm.JARJavaVersion{}))
+	})
+
+	it("reads version from the application manifest", func() {
+		write("META-INF/MANIFEST.MF", []byte("Build-Jdk-Spec: 17\nBuild-Jdk: 17.0.6\n"))
+
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{
+			Attribute: "Build-Jdk-Spec", Path: "META-INF/MANIFEST.MF", Version: "17",
+		}))
+	})
+
+	it("prefers the application manifest to JARs", func() {
+		write("META-INF/MANIFEST.MF", []byte("Build-Jdk-Spec: 11\n"))
+		write("BOOT-INF/lib/alpha.jar", jar(withClass(manifest("Build-Jdk: 17.0.6\n"), 61)))
+
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{
+			Attribute: "Build-Jdk-Spec", Path: "META-INF/MANIFEST.MF", Version: "11",
+		}))
+	})
+
+	it("reads the highest version from JARs and nested JARs", func() {
+		write("BOOT-INF/lib/alpha.jar", jar(withClass(manifest("Build-Jdk: 17.0.6\n"), 61)))
+		write("lib/bravo.jar", jar(withClass(manifest("Created-By: 1.8.0_292 (AdoptOpenJDK)\n"), 52)))
+		write("application.jar", jar(map[string][]byte{
+			"WEB-INF/lib/charlie.jar": jar(withClass(manifest("Created-By: 21.0.1 (Eclipse Adoptium)\n"), 65)),
+			"other/delta.jar":         jar(withClass(manifest("Build-Jdk-Spec: 25\n"), 69)),
+		}))
+
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{
+			Attribute: "Created-By", Path: "application.jar!/WEB-INF/lib/charlie.jar", Version: "21.0.1",
+		}))
+	})
+
+	it("reads version from stored nested JARs", func() {
+		write("application.jar", archive(zip.Store, map[string][]byte{
+			"BOOT-INF/lib/alpha.jar": archive(zip.Store, withClass(manifest("Build-Jdk-Spec: 17\n"), 61)),
+		}))
+
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{
+			Attribute: "Build-Jdk-Spec", Path: "application.jar!/BOOT-INF/lib/alpha.jar", Version: "17",
+		}))
+	})
+
+	it("ignores JAR versions that are not confirmed by their classes", func() {
+		write("lib/alpha.jar", jar(withClass(manifest("Build-Jdk-Spec: 21\n"), 52)))
+		write("lib/bravo.jar", jar(manifest("Build-Jdk-Spec: 25\n")))
+		write("lib/charlie.jar", jar(withClass(manifest("Build-Jdk-Spec: 11\n"), 55)))
+
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{
+			Attribute: "Build-Jdk-Spec", Path: "lib/charlie.jar", Version: "11",
+		}))
+	})
+
+	it("ignores build tool wrappers", func() {
+		write(".mvn/wrapper/maven-wrapper.jar", jar(withClass(manifest("Build-Jdk-Spec: 21\n"), 65)))
+		write("gradle/wrapper/gradle-wrapper.jar", jar(withClass(manifest("Build-Jdk-Spec: 17\n"), 61)))
+
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{}))
+	})
+
+	it("ignores build tools in Created-By", func() {
+		write("lib/alpha.jar", jar(manifest("Created-By: Apache Maven 3.9.6\n")))
+
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{}))
+	})
+
+	it("ignores multi-release JARs", func() {
+		write("lib/alpha.jar", jar(withClass(manifest("Build-Jdk-Spec: 11\n"), 55)))
+		write("lib/bravo.jar", jar(withClass(manifest("Multi-Release: true\nBuild-Jdk-Spec: 21\n"), 65)))
+
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{
+			Attribute: "Build-Jdk-Spec", Path: "lib/alpha.jar", Version: "11",
+		}))
+	})
+
+	it("ignores files that are not JARs", func() {
+		write("lib/alpha.jar", []byte("alpha"))
+
+		Expect(libjvm.ReadJavaVersionFromJARs(appPath)).To(Equal(libjvm.JARJavaVersion{}))
+	})
+}
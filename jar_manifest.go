@@ -0,0 +1,352 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxManifestLineLength is the longest line, in bytes and excluding the line break, written to a manifest.  Longer
+	// attributes are continued on lines starting with a single space.
+	MaxManifestLineLength = 72
+
+	// MaxManifestAttributeNameLength is the longest attribute name, in bytes, allowed in a manifest.
+	MaxManifestAttributeNameLength = 70
+)
+
+// manifestAttributeName matches the attribute names allowed by the JAR File Specification.
+var manifestAttributeName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Attribute is a name and value in a section of a JAR manifest.
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// Attributes are the attributes of a section of a JAR manifest, in the order they appear.  Names are case-insensitive
+// and may be repeated.
+type Attributes []Attribute
+
+// Get returns the value of the first attribute with name.
+func (a Attributes) Get(name string) (string, bool) {
+	for _, at := range a {
+		if strings.EqualFold(at.Name, name) {
+			return at.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// GetAll returns the values of every attribute with name.
+func (a Attributes) GetAll(name string) []string {
+	var values []string
+	for _, at := range a {
+		if strings.EqualFold(at.Name, name) {
+			values = append(values, at.Value)
+		}
+	}
+
+	return values
+}
+
+// Fields returns the space-separated values, such as those of Add-Opens or Class-Path, of every attribute with name.
+func (a Attributes) Fields(name string) []string {
+	var fields []string
+	for _, v := range a.GetAll(name) {
+		fields = append(fields, strings.Fields(v)...)
+	}
+
+	return fields
+}
+
+// Set replaces the value of the first attribute with name and removes any others, or adds the attribute if there are
+// none.
+func (a *Attributes) Set(name string, value string) {
+	var set Attributes
+	found := false
+
+	for _, at := range *a {
+		if !strings.EqualFold(at.Name, name) {
+			set = append(set, at)
+		} else if !found {
+			set = append(set, Attribute{Name: at.Name, Value: value})
+			found = true
+		}
+	}
+
+	if !found {
+		set = append(set, Attribute{Name: name, Value: value})
+	}
+
+	*a = set
+}
+
+// Add adds an attribute, even if one with the same name exists.
+func (a *Attributes) Add(name string, value string) {
+	*a = append(*a, Attribute{Name: name, Value: value})
+}
+
+// ManifestEntry is a per-entry section of a JAR manifest, describing the entry with Name.
+type ManifestEntry struct {
+	// Name is the name of the entry.
+	Name string
+
+	// Attributes are the attributes of the entry, other than Name.
+	Attributes Attributes
+}
+
+// JARManifest is a JAR manifest as defined by the JAR File Specification.  Unlike NewManifest, it keeps per-entry
+// sections and repeated attributes, and can be written.
+type JARManifest struct {
+	// Main are the attributes of the main section.
+	Main Attributes
+
+	// Entries are the per-entry sections, in the order they appear.
+	Entries []ManifestEntry
+}
+
+// Entry returns the first per-entry section for the entry with name.
+func (m JARManifest) Entry(name string) (ManifestEntry, bool) {
+	for _, e := range m.Entries {
+		if e.Name == name {
+			return e, true
+		}
+	}
+
+	return ManifestEntry{}, false
+}
+
+// NewJARManifest reads the <APP>/META-INF/MANIFEST.MF file if it exists.
+func NewJARManifest(applicationPath string) (JARManifest, error) {
+	file := filepath.Join(applicationPath, "META-INF", "MANIFEST.MF")
+
+	in, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return JARManifest{}, nil
+	} else if err != nil {
+		return JARManifest{}, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	m, err := ParseJARManifest(in)
+	if err != nil {
+		return JARManifest{}, fmt.Errorf("unable to parse %s\n%w", file, err)
+	}
+
+	return m, nil
+}
+
+// NewJARManifestFromJAR reads the META-INF/MANIFEST.MF from a JAR file if it exists.
+func NewJARManifestFromJAR(jarFilePath string) (JARManifest, error) {
+	jarFile, err := zip.OpenReader(jarFilePath)
+	if err != nil {
+		return JARManifest{}, fmt.Errorf("unable to read file %s\n%w", jarFilePath, err)
+	}
+	defer jarFile.Close()
+
+	in, err := jarFile.Open("META-INF/MANIFEST.MF")
+	if errors.Is(err, fs.ErrNotExist) {
+		return JARManifest{}, nil
+	} else if err != nil {
+		return JARManifest{}, fmt.Errorf("unable to read MANIFEST.MF in %s\n%w", jarFilePath, err)
+	}
+	defer in.Close()
+
+	m, err := ParseJARManifest(in)
+	if err != nil {
+		return JARManifest{}, fmt.Errorf("unable to parse MANIFEST.MF in %s\n%w", jarFilePath, err)
+	}
+
+	return m, nil
+}
+
+// manifestLine is a logical line of a manifest, with any continuations joined, and the line number it starts on.
+type manifestLine struct {
+	number int
+	text   string
+}
+
+// ParseJARManifest parses a manifest.  Lines may end with CRLF, LF, or CR, and lines starting with a single space
+// continue the previous line.  Sections are separated by blank lines.  The first section is the main section, and every
+// following section must start with a Name attribute.  Unlike the JDK, blank lines before the main section and a final
+// line without a line break are tolerated.
+func ParseJARManifest(reader io.Reader) (JARManifest, error) {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return JARManifest{}, fmt.Errorf("unable to read manifest\n%w", err)
+	}
+
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	b = bytes.ReplaceAll(b, []byte("\r"), []byte("\n"))
+
+	var (
+		sections [][]manifestLine
+		section  []manifestLine
+	)
+
+	for i, l := range strings.Split(string(b), "\n") {
+		switch {
+		case l == "":
+			if section != nil {
+				sections = append(sections, section)
+				section = nil
+			}
+		case l[0] == ' ':
+			if section == nil {
+				return JARManifest{}, fmt.Errorf("continuation on line %d does not follow an attribute", i+1)
+			}
+			section[len(section)-1].text += l[1:]
+		default:
+			section = append(section, manifestLine{number: i + 1, text: l})
+		}
+	}
+	if section != nil {
+		sections = append(sections, section)
+	}
+
+	var m JARManifest
+	for i, s := range sections {
+		attributes, err := parseManifestSection(s)
+		if err != nil {
+			return JARManifest{}, err
+		}
+
+		// an empty main section is written as a leading blank line, which is otherwise tolerated before the main section
+		if i == 0 && !(bytes.HasPrefix(b, []byte("\n")) && strings.EqualFold(attributes[0].Name, "Name")) {
+			m.Main = attributes
+			continue
+		}
+
+		if !strings.EqualFold(attributes[0].Name, "Name") {
+			return JARManifest{}, fmt.Errorf("section on line %d does not start with a Name attribute", s[0].number)
+		}
+		m.Entries = append(m.Entries, ManifestEntry{Name: attributes[0].Value, Attributes: attributes[1:]})
+	}
+
+	return m, nil
+}
+
+func parseManifestSection(lines []manifestLine) (Attributes, error) {
+	var attributes Attributes
+
+	for _, l := range lines {
+		i := strings.IndexByte(l.text, ':')
+		if i < 0 || (i+1 < len(l.text) && l.text[i+1] != ' ') {
+			return nil, fmt.Errorf("invalid attribute on line %d, expected name: value", l.number)
+		}
+
+		name := l.text[:i]
+		if !validManifestAttributeName(name) {
+			return nil, fmt.Errorf("invalid attribute name %q on line %d", name, l.number)
+		}
+
+		value := ""
+		if i+2 <= len(l.text) {
+			value = l.text[i+2:]
+		}
+
+		attributes = append(attributes, Attribute{Name: name, Value: value})
+	}
+
+	return attributes, nil
+}
+
+func validManifestAttributeName(name string) bool {
+	return len(name) <= MaxManifestAttributeNameLength && manifestAttributeName.MatchString(name)
+}
+
+// WriteTo writes the manifest with CRLF line breaks, continuing lines longer than MaxManifestLineLength bytes without
+// splitting UTF-8 characters.  As in the JDK, Manifest-Version, or Signature-Version if there is none, is written
+// first.  Nothing is written if an attribute cannot be represented in a manifest.
+func (m JARManifest) WriteTo(w io.Writer) (int64, error) {
+	b := &bytes.Buffer{}
+
+	main := m.Main
+	for _, n := range []string{"Manifest-Version", "Signature-Version"} {
+		if v, ok := m.Main.Get(n); ok {
+			main = Attributes{{Name: n, Value: v}}
+			for _, a := range m.Main {
+				if !strings.EqualFold(a.Name, n) {
+					main = append(main, a)
+				}
+			}
+			break
+		}
+	}
+
+	if err := writeManifestSection(b, main); err != nil {
+		return 0, err
+	}
+
+	for _, e := range m.Entries {
+		if e.Name == "" {
+			return 0, fmt.Errorf("invalid manifest entry without a name")
+		}
+		if _, ok := e.Attributes.Get("Name"); ok {
+			return 0, fmt.Errorf("invalid manifest entry %s with a Name attribute", e.Name)
+		}
+
+		if err := writeManifestSection(b, append(Attributes{{Name: "Name", Value: e.Name}}, e.Attributes...)); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.Write(b.Bytes())
+	return int64(n), err
+}
+
+func writeManifestSection(b *bytes.Buffer, attributes Attributes) error {
+	for _, a := range attributes {
+		if !validManifestAttributeName(a.Name) {
+			return fmt.Errorf("invalid attribute name %q", a.Name)
+		}
+		if strings.ContainsAny(a.Value, "\r\n\x00") || !utf8.ValidString(a.Value) {
+			return fmt.Errorf("invalid value for attribute %s, must be UTF-8 without line breaks or NUL", a.Name)
+		}
+
+		line := a.Name + ": " + a.Value
+		limit := MaxManifestLineLength
+		for len(line) > limit {
+			i := limit
+			for !utf8.RuneStart(line[i]) {
+				i--
+			}
+
+			b.WriteString(line[:i])
+			b.WriteString("\r\n ")
+			line = line[i:]
+			limit = MaxManifestLineLength - 1
+		}
+		b.WriteString(line)
+		b.WriteString("\r\n")
+	}
+
+	b.WriteString("\r\n")
+	return nil
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testJARManifest(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	get := func(a libjvm.Attributes, name string) string {
+		v, ok := a.Get(name)
+		Expect(ok).To(BeTrue())
+		return v
+	}
+
+	parse := func(s string) (libjvm.JARManifest, error) {
+		return libjvm.ParseJARManifest(strings.NewReader(s))
+	}
+
+	context("parse", func() {
+		it("parses main and per-entry sections", func() {
+			m, err := parse("Manifest-Version: 1.0\r\n" +
+				"Main-Class: org.springframework.boot.loader.launch.JarLauncher\r\n" +
+				"Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx\r\n" +
+				"\r\n" +
+				"Name: com/example/\r\n" +
+				"Sealed: true\r\n" +
+				"\r\n" +
+				"Name: com/example/Alpha.class\r\n" +
+				"SHA-256-Digest: abc=\r\n" +
+				"\r\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(get(m.Main, "Spring-Boot-Classpath-Index")).To(Equal("BOOT-INF/classpath.idx"))
+			Expect(m.Entries).To(Equal([]libjvm.ManifestEntry{
+				{Name: "com/example/", Attributes: libjvm.Attributes{{Name: "Sealed", Value: "true"}}},
+				{Name: "com/example/Alpha.class", Attributes: libjvm.Attributes{{Name: "SHA-256-Digest", Value: "abc="}}},
+			}))
+
+			e, ok := m.Entry("com/example/")
+			Expect(ok).To(BeTrue())
+			Expect(get(e.Attributes, "sealed")).To(Equal("true"))
+		})
+
+		it("joins continuation lines exactly", func() {
+			m, err := parse("Start-Class: org.springframework.samples.petclinic.PetClinicApplicatio\n n\nBravo: charlie \n  delta\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(get(m.Main, "Start-Class")).To(Equal("org.springframework.samples.petclinic.PetClinicApplication"))
+			Expect(get(m.Main, "Bravo")).To(Equal("charlie  delta"))
+		})
+
+		it("does not treat = as a separator", func() {
+			m, err := parse("Add-Opens: java.base/java.lang=ALL-UNNAMED\nLauncher-Agent-Class: com.example.Agent\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(get(m.Main, "Add-Opens")).To(Equal("java.base/java.lang=ALL-UNNAMED"))
+			Expect(get(m.Main, "Launcher-Agent-Class")).To(Equal("com.example.Agent"))
+		})
+
+		it("keeps repeated attributes", func() {
+			m, err := parse("Add-Opens: java.base/java.lang\nadd-opens: java.base/java.util java.base/java.io\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(get(m.Main, "Add-Opens")).To(Equal("java.base/java.lang"))
+			Expect(m.Main.GetAll("Add-Opens")).To(Equal([]string{"java.base/java.lang", "java.base/java.util java.base/java.io"}))
+			Expect(m.Main.Fields("Add-Opens")).To(Equal([]string{"java.base/java.lang", "java.base/java.util", "java.base/java.io"}))
+		})
+
+		it("tolerates leading blank lines and a missing final line break", func() {
+			m, err := parse("\nManifest-Version: 1.0")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Main).To(Equal(libjvm.Attributes{{Name: "Manifest-Version", Value: "1.0"}}))
+		})
+
+		it("parses an empty main section", func() {
+			m, err := parse("\r\nName: alpha\r\nBravo: charlie\r\n\r\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Main).To(BeEmpty())
+			Expect(m.Entries).To(HaveLen(1))
+		})
+
+		it("fails on invalid attributes", func() {
+			_, err := parse("Manifest-Version: 1.0\ntest-key=test-value\n")
+			Expect(err).To(MatchError("invalid attribute on line 2, expected name: value"))
+
+			_, err = parse("Manifest-Version:1.0\n")
+			Expect(err).To(MatchError("invalid attribute on line 1, expected name: value"))
+
+			_, err = parse("Bad Name: value\n")
+			Expect(err).To(MatchError(`invalid attribute name "Bad Name" on line 1`))
+		})
+
+		it("fails on continuations without an attribute", func() {
+			_, err := parse(" alpha\n")
+			Expect(err).To(MatchError("continuation on line 1 does not follow an attribute"))
+		})
+
+		it("fails on sections without a Name", func() {
+			_, err := parse("Manifest-Version: 1.0\n\nSealed: true\n")
+			Expect(err).To(MatchError("section on line 3 does not start with a Name attribute"))
+		})
+	})
+
+	context("write", func() {
+		it("writes sections with Manifest-Version first", func() {
+			m := libjvm.JARManifest{
+				Main: libjvm.Attributes{{Name: "Main-Class", Value: "com.example.Main"}, {Name: "Manifest-Version", Value: "1.0"}},
+				Entries: []libjvm.ManifestEntry{
+					{Name: "com/example/", Attributes: libjvm.Attributes{{Name: "Sealed", Value: "true"}}},
+				},
+			}
+
+			b := &bytes.Buffer{}
+			Expect(m.WriteTo(b)).To(BeEquivalentTo(b.Len()))
+			Expect(b.String()).To(Equal("Manifest-Version: 1.0\r\nMain-Class: com.example.Main\r\n\r\nName: com/example/\r\nSealed: true\r\n\r\n"))
+		})
+
+		it("continues lines longer than 72 bytes without splitting characters", func() {
+			value := strings.Repeat("a", 60) + strings.Repeat("é", 50)
+			m := libjvm.JARManifest{Main: libjvm.Attributes{{Name: "Alpha", Value: value}}}
+
+			b := &bytes.Buffer{}
+			_, err := m.WriteTo(b)
+			Expect(err).NotTo(HaveOccurred())
+
+			lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n\r\n"), "\r\n")
+			Expect(len(lines)).To(BeNumerically(">", 1))
+			for i, l := range lines {
+				Expect(len(l)).To(BeNumerically("<=", libjvm.MaxManifestLineLength))
+				Expect(strings.ToValidUTF8(l, "?")).To(Equal(l))
+				if i > 0 {
+					Expect(l).To(HavePrefix(" "))
+				}
+			}
+
+			r, err := libjvm.ParseJARManifest(b)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get(r.Main, "Alpha")).To(Equal(value))
+		})
+
+		it("round trips", func() {
+			m := libjvm.JARManifest{
+				Main: libjvm.Attributes{
+					{Name: "Manifest-Version", Value: "1.0"},
+					{Name: "Add-Opens", Value: "java.base/java.lang=ALL-UNNAMED"},
+					{Name: "Add-Opens", Value: "java.base/java.util"},
+				},
+				Entries: []libjvm.ManifestEntry{
+					{Name: strings.Repeat("com/example/", 10), Attributes: libjvm.Attributes{{Name: "Sealed", Value: "true"}}},
+				},
+			}
+
+			b := &bytes.Buffer{}
+			_, err := m.WriteTo(b)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(libjvm.ParseJARManifest(b)).To(Equal(m))
+		})
+
+		it("round trips an empty main section", func() {
+			m := libjvm.JARManifest{Entries: []libjvm.ManifestEntry{{Name: "alpha", Attributes: libjvm.Attributes{{Name: "Bravo", Value: "charlie"}}}}}
+
+			b := &bytes.Buffer{}
+			_, err := m.WriteTo(b)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(libjvm.ParseJARManifest(b)).To(Equal(m))
+		})
+
+		it("fails on attributes that cannot be written", func() {
+			b := &bytes.Buffer{}
+
+			_, err := libjvm.JARManifest{Main: libjvm.Attributes{{Name: "Alpha", Value: "bravo\ncharlie"}}}.WriteTo(b)
+			Expect(err).To(MatchError(ContainSubstring("invalid value for attribute Alpha")))
+
+			_, err = libjvm.JARManifest{Main: libjvm.Attributes{{Name: "Alpha:", Value: "bravo"}}}.WriteTo(b)
+			Expect(err).To(MatchError(`invalid attribute name "Alpha:"`))
+
+			_, err = libjvm.JARManifest{Entries: []libjvm.ManifestEntry{{}}}.WriteTo(b)
+			Expect(err).To(HaveOccurred())
+
+			Expect(b.Len()).To(BeZero())
+		})
+	})
+
+	context("attributes", func() {
+		it("sets and adds attributes", func() {
+			a := libjvm.Attributes{{Name: "Alpha", Value: "1"}, {Name: "Bravo", Value: "2"}, {Name: "alpha", Value: "3"}}
+
+			a.Set("ALPHA", "4")
+			Expect(a).To(Equal(libjvm.Attributes{{Name: "Alpha", Value: "4"}, {Name: "Bravo", Value: "2"}}))
+
+			a.Set("Charlie", "5")
+			a.Add("Bravo", "6")
+			Expect(a).To(Equal(libjvm.Attributes{
+				{Name: "Alpha", Value: "4"}, {Name: "Bravo", Value: "2"}, {Name: "Charlie", Value: "5"}, {Name: "Bravo", Value: "6"},
+			}))
+		})
+	})
+
+	context("files", func() {
+		var path string
+
+		it.Before(func() {
+			path = t.TempDir()
+		})
+
+		it("returns an empty manifest if file doesn't exist", func() {
+			Expect(libjvm.NewJARManifest(path)).To(Equal(libjvm.JARManifest{}))
+		})
+
+		it("reads the application manifest", func() {
+			Expect(os.MkdirAll(filepath.Join(path, "META-INF"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(path, "META-INF", "MANIFEST.MF"), []byte("Main-Class: com.example.Main\n"), 0644)).To(Succeed())
+
+			m, err := libjvm.NewJARManifest(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get(m.Main, "Main-Class")).To(Equal("com.example.Main"))
+		})
+
+		it("reads the manifest of a JAR", func() {
+			file := filepath.Join(path, "test.jar")
+			f, err := os.Create(file)
+			Expect(err).NotTo(HaveOccurred())
+			z := zip.NewWriter(f)
+			w, err := z.Create("META-INF/MANIFEST.MF")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte("Main-Class: com.example.Main\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(z.Close()).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+
+			m, err := libjvm.NewJARManifestFromJAR(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get(m.Main, "Main-Class")).To(Equal("com.example.Main"))
+		})
+	})
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/paketo-buildpacks/libpak"
+)
+
+// VersionFallback determines which Java version is used when the version requested by an application is not provided
+// by the buildpack.
+type VersionFallback string
+
+const (
+	// VersionFallbackNone fails the build.
+	VersionFallbackNone VersionFallback = "none"
+
+	// VersionFallbackNextLTS uses the next long-term support version that is provided.
+	VersionFallbackNextLTS VersionFallback = "next-lts"
+
+	// VersionFallbackNextAvailable uses the next version that is provided.
+	VersionFallbackNextAvailable VersionFallback = "next-available"
+)
+
+// ParseVersionFallback parses a case-insensitive VersionFallback, such as the value of $BP_JVM_VERSION_FALLBACK.
+func ParseVersionFallback(s string) (VersionFallback, error) {
+	switch f := VersionFallback(strings.ToLower(strings.TrimSpace(s))); f {
+	case VersionFallbackNone, VersionFallbackNextLTS, VersionFallbackNextAvailable:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown version fallback %q, must be one of %s, %s or %s",
+			s, VersionFallbackNone, VersionFallbackNextLTS, VersionFallbackNextAvailable)
+	}
+}
+
+// Next returns the first version after version that is provided by the dependencies and allowed by the fallback.  A
+// pinned patch version falls forward to the latest patch of the same major version first.  Versions without a major
+// version, such as ranges, have no next version.
+func (f VersionFallback) Next(dr libpak.DependencyResolver, version string) (string, bool) {
+	if f == VersionFallbackNone {
+		return "", false
+	}
+
+	major, err := strconv.Atoi(extractMajorVersion(NormalizeJavaVersion(version)))
+	if err != nil {
+		return "", false
+	}
+
+	provided := make(map[int]bool)
+	for _, d := range dr.Dependencies {
+		if d.ID != "jdk" && d.ID != "jre" {
+			continue
+		}
+		if v, err := semver.NewVersion(d.Version); err == nil {
+			provided[int(v.Major())] = true
+		}
+	}
+
+	var majors []int
+	for m := range provided {
+		majors = append(majors, m)
+	}
+	sort.Ints(majors)
+
+	for _, m := range majors {
+		candidate := strconv.Itoa(m)
+		if m < major || candidate == version {
+			continue
+		}
+		if f == VersionFallbackNextLTS && !isLTSJavaVersion(m) {
+			continue
+		}
+		if isJavaVersionAvailable(dr, candidate) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// isLTSJavaVersion returns whether a major version is a long-term support release.  Since Java 17, every fourth
+// release is.
+func isLTSJavaVersion(major int) bool {
+	return major == 8 || major == 11 || (major >= 17 && (major-17)%4 == 0)
+}
+
+// isJavaVersionAvailable returns whether the dependencies provide a JDK or JRE for version.  Errors other than a
+// missing dependency are treated as available, so that they are reported when the dependency is resolved.
+func isJavaVersionAvailable(dr libpak.DependencyResolver, version string) bool {
+	_, jdkErr := dr.Resolve("jdk", version)
+	_, jreErr := dr.Resolve("jre", version)
+	return !(libpak.IsNoValidDependencies(jdkErr) && libpak.IsNoValidDependencies(jreErr))
+}
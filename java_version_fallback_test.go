@@ -0,0 +1,86 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testJavaVersionFallback(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dr = libpak.DependencyResolver{
+			Dependencies: []libpak.BuildpackDependency{
+				{ID: "jre", Version: "8.0.432", Stacks: []string{"test-stack-id"}},
+				{ID: "jre", Version: "17.0.13", Stacks: []string{"test-stack-id"}},
+				{ID: "jdk", Version: "23.0.1", Stacks: []string{"test-stack-id"}},
+				{ID: "jre", Version: "25.0.1", Stacks: []string{"test-stack-id"}},
+				{ID: "native-image-svm", Version: "22.0.2", Stacks: []string{"test-stack-id"}},
+			},
+			StackID: "test-stack-id",
+		}
+	)
+
+	it("parses fallbacks", func() {
+		Expect(libjvm.ParseVersionFallback("none")).To(Equal(libjvm.VersionFallbackNone))
+		Expect(libjvm.ParseVersionFallback("Next-LTS")).To(Equal(libjvm.VersionFallbackNextLTS))
+		Expect(libjvm.ParseVersionFallback("next-available")).To(Equal(libjvm.VersionFallbackNextAvailable))
+
+		_, err := libjvm.ParseVersionFallback("latest")
+		Expect(err).To(MatchError(ContainSubstring(`unknown version fallback "latest"`)))
+	})
+
+	it("does not fall back with none", func() {
+		_, ok := libjvm.VersionFallbackNone.Next(dr, "20")
+		Expect(ok).To(BeFalse())
+	})
+
+	it("falls back to the next available version", func() {
+		Expect(nextVersion(libjvm.VersionFallbackNextAvailable.Next(dr, "20"))).To(Equal("23"))
+		Expect(nextVersion(libjvm.VersionFallbackNextAvailable.Next(dr, "1.7"))).To(Equal("8"))
+	})
+
+	it("falls back to the next LTS version", func() {
+		Expect(nextVersion(libjvm.VersionFallbackNextLTS.Next(dr, "20"))).To(Equal("25"))
+	})
+
+	it("falls back to the latest patch of a pinned version", func() {
+		Expect(nextVersion(libjvm.VersionFallbackNextAvailable.Next(dr, "17.0.2"))).To(Equal("17"))
+	})
+
+	it("does not fall back without a later version", func() {
+		_, ok := libjvm.VersionFallbackNextAvailable.Next(dr, "26")
+		Expect(ok).To(BeFalse())
+
+		_, ok = libjvm.VersionFallbackNextLTS.Next(dr, ">=26")
+		Expect(ok).To(BeFalse())
+	})
+}
+
+func nextVersion(version string, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return version
+}
@@ -0,0 +1,267 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/heroku/color"
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// JavaVersionSource is a project file that may declare the Java version of an application.
+type JavaVersionSource struct {
+	// File is the path of the file, relative to the application.
+	File string
+
+	// Read returns the Java version declared in the file, or an empty string if it declares none or does not exist.
+	Read func(file string) (string, error)
+
+	// Strict is whether a file that cannot be read fails the build.  Other files are skipped with a warning, as they
+	// may be malformed or belong to another tool without preventing the application from being built.
+	Strict bool
+}
+
+// JavaVersionSources are the project files that declare a Java version, in order of precedence. Version manager
+// files, which state the JVM to use, take precedence over build files, which state the version to compile for.
+var JavaVersionSources = []JavaVersionSource{
+	{File: ".sdkmanrc", Read: readJavaVersionFromSDKMANRC, Strict: true},
+	{File: ".java-version", Read: readJavaVersionFromJavaVersion},
+	{File: ".tool-versions", Read: readJavaVersionFromToolVersions},
+	{File: "mise.toml", Read: readJavaVersionFromMise},
+	{File: ".mise.toml", Read: readJavaVersionFromMise},
+	{File: "pom.xml", Read: readJavaVersionFromPOM},
+	{File: "build.gradle.kts", Read: readJavaVersionFromGradle},
+	{File: "build.gradle", Read: readJavaVersionFromGradle},
+	{File: "system.properties", Read: readJavaVersionFromSystemProperties},
+}
+
+// ReadJavaVersionFromSources returns the Java version from the first source in the application that declares one, and
+// the file that declared it.  Sources that cannot be read are skipped with a warning unless they are strict.
+func ReadJavaVersionFromSources(appPath string, logger bard.Logger) (string, string, error) {
+	for _, s := range JavaVersionSources {
+		file := filepath.Join(appPath, s.File)
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", "", fmt.Errorf("unable to stat %s\n%w", file, err)
+		}
+
+		v, err := s.Read(file)
+		if err != nil && s.Strict {
+			return "", "", fmt.Errorf("unable to read Java version from %s\n%w", s.File, err)
+		} else if err != nil {
+			logger.Body(color.New(color.Faint, color.Bold).Sprintf(
+				"WARNING: unable to read Java version from %s, skipping it\n%s", s.File, err))
+			continue
+		}
+
+		if v != "" {
+			return v, s.File, nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// versionPattern finds a version either at the start of a string or following a vendor prefix, such as
+// temurin-17.0.8+7.
+var versionPattern = regexp.MustCompile(`(?:^|-)(\d+(?:\.\d+)*)`)
+
+func parseVersion(s string) string {
+	s = strings.TrimSpace(s)
+	if IsJavaVersionRange(s) {
+		return s
+	}
+
+	if m := versionPattern.FindStringSubmatch(s); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func readJavaVersionFromSDKMANRC(file string) (string, error) {
+	components, err := ReadSDKMANRC(file)
+	if err != nil {
+		return "", err
+	}
+
+	for _, component := range components {
+		if component.Type == "java" {
+			return component.Version, nil
+		}
+	}
+
+	return "", nil
+}
+
+func readJavaVersionFromJavaVersion(file string) (string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	return parseVersion(string(b)), nil
+}
+
+func readJavaVersionFromToolVersions(file string) (string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		f := strings.Fields(strings.SplitN(s.Text(), "#", 2)[0])
+		if len(f) < 2 || f[0] != "java" {
+			continue
+		}
+
+		// asdf falls back through the listed versions, the first is preferred
+		return parseVersion(f[1]), nil
+	}
+
+	return "", nil
+}
+
+func readJavaVersionFromMise(file string) (string, error) {
+	var m struct {
+		Tools map[string]interface{} `toml:"tools"`
+	}
+	if _, err := toml.DecodeFile(file, &m); err != nil {
+		return "", fmt.Errorf("unable to decode %s\n%w", file, err)
+	}
+
+	switch t := m.Tools["java"].(type) {
+	case string:
+		return parseVersion(t), nil
+	case []interface{}:
+		if len(t) > 0 {
+			if s, ok := t[0].(string); ok {
+				return parseVersion(s), nil
+			}
+		}
+	case map[string]interface{}:
+		if s, ok := t["version"].(string); ok {
+			return parseVersion(s), nil
+		}
+	}
+
+	return "", nil
+}
+
+type pomProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type pom struct {
+	Properties struct {
+		Entries []pomProperty `xml:",any"`
+	} `xml:"properties"`
+	Plugins []struct {
+		ArtifactID    string `xml:"artifactId"`
+		Configuration struct {
+			Release string `xml:"release"`
+		} `xml:"configuration"`
+	} `xml:"build>plugins>plugin"`
+}
+
+func readJavaVersionFromPOM(file string) (string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	var p pom
+	if err := xml.Unmarshal(b, &p); err != nil {
+		return "", fmt.Errorf("unable to decode %s\n%w", file, err)
+	}
+
+	properties := make(map[string]string)
+	for _, e := range p.Properties.Entries {
+		properties[e.XMLName.Local] = strings.TrimSpace(e.Value)
+	}
+
+	resolve := func(s string) string {
+		s = strings.TrimSpace(s)
+		if strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}") {
+			return properties[strings.TrimSuffix(strings.TrimPrefix(s, "${"), "}")]
+		}
+		return s
+	}
+
+	for _, p := range p.Plugins {
+		if p.ArtifactID == "maven-compiler-plugin" {
+			if v := parseVersion(resolve(p.Configuration.Release)); v != "" {
+				return v, nil
+			}
+		}
+	}
+
+	for _, k := range []string{"maven.compiler.release", "java.version", "maven.compiler.target", "maven.compiler.source"} {
+		if v := parseVersion(resolve(properties[k])); v != "" {
+			return v, nil
+		}
+	}
+
+	return "", nil
+}
+
+var (
+	gradleToolchain     = regexp.MustCompile(`JavaLanguageVersion\.of\(\s*["']?(\d+)["']?\s*\)`)
+	gradleCompatibility = regexp.MustCompile(`(?:source|target)Compatibility\s*=\s*(?:JavaVersion\.VERSION_(\d+(?:_\d+)?)|["']?(\d+(?:\.\d+)?)["']?)`)
+)
+
+func readJavaVersionFromGradle(file string) (string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	if m := gradleToolchain.FindSubmatch(b); m != nil {
+		return string(m[1]), nil
+	}
+
+	if m := gradleCompatibility.FindSubmatch(b); m != nil {
+		if len(m[1]) > 0 {
+			return strings.ReplaceAll(string(m[1]), "_", "."), nil
+		}
+		return string(m[2]), nil
+	}
+
+	return "", nil
+}
+
+func readJavaVersionFromSystemProperties(file string) (string, error) {
+	p, err := properties.LoadFile(file, properties.UTF8)
+	if err != nil {
+		return "", fmt.Errorf("unable to read properties file %s\n%w", file, err)
+	}
+
+	v, _ := p.Get("java.runtime.version")
+	return parseVersion(v), nil
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testJavaVersionSources(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+		b       *bytes.Buffer
+	)
+
+	it.Before(func() {
+		appPath = t.TempDir()
+		b = &bytes.Buffer{}
+	})
+
+	write := func(file string, content string) {
+		Expect(os.WriteFile(filepath.Join(appPath, file), []byte(content), 0644)).To(Succeed())
+	}
+
+	expectVersion := func(version string, source string) {
+		v, s, err := libjvm.ReadJavaVersionFromSources(appPath, bard.NewLogger(b))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal(version))
+		Expect(s).To(Equal(source))
+	}
+
+	it("returns nothing without sources", func() {
+		expectVersion("", "")
+	})
+
+	it("reads .java-version", func() {
+		write(".java-version", "temurin-17.0.8+7\n")
+		expectVersion("17.0.8", ".java-version")
+	})
+
+	it("reads version ranges", func() {
+		write(".java-version", ">=17.0.5\n")
+		expectVersion(">=17.0.5", ".java-version")
+	})
+
+	it("reads .tool-versions", func() {
+		write(".tool-versions", "nodejs 20.1.0\njava semeru-openj9-21.0.1+12_openj9-0.42.0 17.0.2 # comment\n")
+		expectVersion("21.0.1", ".tool-versions")
+	})
+
+	it("reads mise.toml", func() {
+		write("mise.toml", "[tools]\njava = \"corretto-21\"\n")
+		expectVersion("21", "mise.toml")
+	})
+
+	it("reads mise.toml with tool options", func() {
+		write(".mise.toml", "[tools]\njava = { version = \"17.0.2\" }\n")
+		expectVersion("17.0.2", ".mise.toml")
+	})
+
+	it("reads maven.compiler.release from pom.xml", func() {
+		write("pom.xml", `<project>
+  <properties>
+    <java.version>17</java.version>
+    <maven.compiler.release>21</maven.compiler.release>
+  </properties>
+</project>`)
+		expectVersion("21", "pom.xml")
+	})
+
+	it("reads java.version from pom.xml", func() {
+		write("pom.xml", `<project><properties><java.version>1.8</java.version></properties></project>`)
+		expectVersion("1.8", "pom.xml")
+	})
+
+	it("reads compiler plugin release from pom.xml", func() {
+		write("pom.xml", `<project>
+  <properties><jdk>11</jdk></properties>
+  <build><plugins><plugin>
+    <artifactId>maven-compiler-plugin</artifactId>
+    <configuration><release>${jdk}</release></configuration>
+  </plugin></plugins></build>
+</project>`)
+		expectVersion("11", "pom.xml")
+	})
+
+	it("reads toolchain from build.gradle.kts", func() {
+		write("build.gradle.kts", `java {
+    toolchain {
+        languageVersion.set(JavaLanguageVersion.of(21))
+    }
+}`)
+		expectVersion("21", "build.gradle.kts")
+	})
+
+	it("reads sourceCompatibility from build.gradle", func() {
+		write("build.gradle", "sourceCompatibility = JavaVersion.VERSION_1_8\n")
+		expectVersion("1.8", "build.gradle")
+	})
+
+	it("reads system.properties", func() {
+		write("system.properties", "java.runtime.version=17\n")
+		expectVersion("17", "system.properties")
+	})
+
+	it("skips sources without a version", func() {
+		write(".tool-versions", "nodejs 20.1.0\n")
+		write("pom.xml", `<project><properties><java.version>17</java.version></properties></project>`)
+		expectVersion("17", "pom.xml")
+	})
+
+	it("prefers version manager files over build files", func() {
+		write("build.gradle", "java { toolchain { languageVersion = JavaLanguageVersion.of(17) } }\n")
+		write(".java-version", "21\n")
+		write(".sdkmanrc", "java=11.0.2-tem\n")
+		expectVersion("11.0.2", ".sdkmanrc")
+	})
+
+	it("skips sources that cannot be read", func() {
+		write("mise.toml", "[tools\n")
+		write("pom.xml", "<project><properties>")
+		write("system.properties", "java.runtime.version=17\n")
+		expectVersion("17", "system.properties")
+		Expect(b.String()).To(ContainSubstring("WARNING: unable to read Java version from mise.toml, skipping it"))
+		Expect(b.String()).To(ContainSubstring("WARNING: unable to read Java version from pom.xml, skipping it"))
+	})
+
+	it("fails if .sdkmanrc cannot be read", func() {
+		Expect(os.Mkdir(filepath.Join(appPath, ".sdkmanrc"), 0755)).To(Succeed())
+
+		_, _, err := libjvm.ReadJavaVersionFromSources(appPath, bard.NewLogger(b))
+		Expect(err).To(MatchError(ContainSubstring("unable to read Java version from .sdkmanrc")))
+	})
+}
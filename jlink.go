@@ -3,6 +3,7 @@ package libjvm
 import (
 	"bytes"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -11,6 +12,7 @@ import (
 	"github.com/buildpacks/libcnb"
 	"github.com/heroku/color"
 	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libjvm/calc"
 	"github.com/paketo-buildpacks/libjvm/count"
 	"github.com/paketo-buildpacks/libpak"
 	"github.com/paketo-buildpacks/libpak/bard"
@@ -25,7 +27,10 @@ type JLink struct {
 	CertificateLoader CertificateLoader
 	Metadata          map[string]interface{}
 	JavaVersion       string
+	JDKDigest         string
 	Args              []string
+	ExtraModules      []string
+	Profile           JLinkProfile
 	UserConfigured    bool
 }
 
@@ -61,29 +66,56 @@ func NewJLink(applicationPath string, exec effect.Executor, args []string, certi
 func (j JLink) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 	j.LayerContributor.Logger = j.Logger
 
+	// modules are resolved before the layer is checked for reuse so that a change to them causes it to be rebuilt
+	var modules string
+	valid := true
+	if j.UserConfigured {
+		valid = j.validArgs()
+	}
+	if !j.UserConfigured || !valid {
+		var err error
+		if modules, err = j.resolveModules(layer.Path); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to retrieve list of JVM modules for jlink\n%w", err)
+		}
+	} else if len(j.ExtraModules) > 0 {
+		modules = strings.Join(j.ExtraModules, ",")
+		j.Logger.Bodyf("Adding extra modules %s", modules)
+	}
+
+	expected := map[string]interface{}{}
+	if m, ok := j.LayerContributor.ExpectedMetadata.(map[string]interface{}); ok {
+		for k, v := range m {
+			expected[k] = v
+		}
+	}
+	if j.JDKDigest != "" {
+		expected["jdk-digest"] = j.JDKDigest
+	}
+	if j.Profile != "" {
+		expected["jlink-profile"] = j.Profile
+		j.Args = j.Profile.Merge(j.Args, j.JavaVersion)
+	}
+	if modules != "" {
+		expected["jlink-modules"] = strings.Split(modules, ",")
+		j.Args = append(j.Args, "--add-modules", modules)
+	}
+	j.LayerContributor.ExpectedMetadata = expected
+
 	return j.LayerContributor.Contribute(layer, func() (libcnb.Layer, error) {
 
 		if err := os.RemoveAll(layer.Path); err != nil {
 			return libcnb.Layer{}, fmt.Errorf("unable to remove jlink layer dir \n%w", err)
 		}
 
-		valid := true
-		if j.UserConfigured {
-			valid = j.validArgs()
-		}
-		if !j.UserConfigured || !valid {
-			modules, err := j.listJVMModules(layer.Path)
-			if err != nil {
-				return libcnb.Layer{}, fmt.Errorf("unable to retrieve list of JVM modules for jlink\n%w", err)
-			}
-			j.Args = append(j.Args, "--add-modules", modules)
-		}
-
 		j.Args = append(j.Args, "--output", layer.Path)
 		if err := j.buildCustomJRE(layer.Path); err != nil {
 			return libcnb.Layer{}, fmt.Errorf("unable to build custom JRE with jlink \n%w", err)
 		}
 
+		if err := j.reportSize(layer.Path); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to report custom JRE size\n%w", err)
+		}
+
 		cacertsPath := filepath.Join(layer.Path, "lib", "security", "cacerts")
 		if err := os.Chmod(cacertsPath, 0664); err != nil {
 			return libcnb.Layer{}, fmt.Errorf("unable to set keystore file permissions\n%w", err)
@@ -149,6 +181,54 @@ func (j *JLink) buildCustomJRE(layerPath string) error {
 	return nil
 }
 
+// reportSize logs the size of the custom JRE against the size of the JDK it was built from, excluding the jmods that
+// only jlink requires.
+func (j *JLink) reportSize(layerPath string) error {
+	jdk := filepath.Join(filepath.Dir(layerPath), "jdk")
+	before, err := dirSize(jdk, filepath.Join(jdk, "jmods"))
+	if err != nil {
+		return fmt.Errorf("unable to determine size of %s\n%w", jdk, err)
+	}
+
+	after, err := dirSize(layerPath, "")
+	if err != nil {
+		return fmt.Errorf("unable to determine size of %s\n%w", layerPath, err)
+	}
+
+	j.Logger.Bodyf("Custom JRE is %s, down from %s for the JDK", calc.Size{Value: after}, calc.Size{Value: before})
+	return nil
+}
+
+func dirSize(path string, exclude string) (int64, error) {
+	var size int64
+
+	if err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		} else if err != nil {
+			return err
+		}
+
+		if d.IsDir() && path == exclude {
+			return filepath.SkipDir
+		}
+
+		if d.Type().IsRegular() {
+			i, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += i.Size()
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
 func (j *JLink) validArgs() bool {
 	jlinkArgs := j.Args[:0]
 	var skipNext, modsFound bool
@@ -175,6 +255,100 @@ func (j *JLink) validArgs() bool {
 	return modsFound
 }
 
+// resolveModules returns the modules required by the application, as discovered by jdeps, together with any extra
+// modules.  If the application has nothing for jdeps to analyze, or jdeps fails, all java.* modules are used instead.
+func (j *JLink) resolveModules(layerPath string) (string, error) {
+	targets, err := j.jdepsTargets()
+	if err != nil {
+		return "", fmt.Errorf("unable to find application classes and JARs\n%w", err)
+	}
+
+	var modules string
+	if len(targets) == 0 {
+		j.Logger.Bodyf("No application classes or JARs found, using all java.* modules")
+		if modules, err = j.listJVMModules(layerPath); err != nil {
+			return "", err
+		}
+	} else if modules, err = j.discoverModules(layerPath, targets); err != nil {
+		j.Logger.Body(color.New(color.Faint, color.Bold).Sprintf("WARNING: unable to discover modules with jdeps, using all java.* modules\n%s", err))
+		if modules, err = j.listJVMModules(layerPath); err != nil {
+			return "", err
+		}
+	}
+
+	var mods []string
+	seen := make(map[string]bool)
+	for _, m := range append(strings.Split(modules, ","), j.ExtraModules...) {
+		if m = strings.TrimSpace(m); m != "" && !seen[m] {
+			seen[m] = true
+			mods = append(mods, m)
+		}
+	}
+
+	j.Logger.Bodyf("Using modules %s", strings.Join(mods, ","))
+	return strings.Join(mods, ","), nil
+}
+
+// jdepsTargets returns the application classes directories and JARs, including those in BOOT-INF/lib and WEB-INF/lib.
+func (j *JLink) jdepsTargets() ([]string, error) {
+	var targets []string
+
+	for _, d := range []string{"BOOT-INF/classes", "WEB-INF/classes"} {
+		file := filepath.Join(j.ApplicationPath, d)
+		if fi, err := os.Stat(file); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("unable to stat %s\n%w", file, err)
+		} else if err == nil && fi.IsDir() {
+			targets = append(targets, file)
+		}
+	}
+
+	for _, p := range []string{"*.jar", "BOOT-INF/lib/*.jar", "WEB-INF/lib/*.jar"} {
+		jars, err := filepath.Glob(filepath.Join(j.ApplicationPath, p))
+		if err != nil {
+			return nil, fmt.Errorf("unable to glob %s\n%w", p, err)
+		}
+		targets = append(targets, jars...)
+	}
+
+	if len(targets) == 0 {
+		file := filepath.Join(j.ApplicationPath, "META-INF", "MANIFEST.MF")
+		if _, err := os.Stat(file); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("unable to stat %s\n%w", file, err)
+		} else if err == nil {
+			targets = append(targets, j.ApplicationPath)
+		}
+	}
+
+	return targets, nil
+}
+
+func (j *JLink) discoverModules(layerPath string, targets []string) (string, error) {
+	release := "base"
+	if j.JavaVersion != "" {
+		release = extractMajorVersion(j.JavaVersion)
+	}
+
+	args := []string{"--print-module-deps", "--ignore-missing-deps", "--multi-release", release}
+
+	buf := &bytes.Buffer{}
+	if err := j.Executor.Execute(effect.Execution{
+		Command: filepath.Join(filepath.Dir(layerPath), "jdk", "bin", "jdeps"),
+		Args:    append(args, targets...),
+		Stdout:  buf,
+		Stderr:  j.Logger.Logger.InfoWriter(),
+	}); err != nil {
+		return "", fmt.Errorf("unable to run jdeps\n%w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	modules := strings.TrimSpace(lines[len(lines)-1])
+	if modules == "" {
+		return "", fmt.Errorf("jdeps returned no modules")
+	}
+
+	return modules, nil
+}
+
 func (j *JLink) listJVMModules(layerPath string) (string, error) {
 	var mods []string
 	buf := &bytes.Buffer{}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JLinkProfile is a curated set of jlink arguments.
+type JLinkProfile string
+
+const (
+	// JLinkMinimal produces the smallest JRE, without debug information, header files, or man pages.
+	JLinkMinimal JLinkProfile = "minimal"
+
+	// JLinkDebuggable keeps debug information, but omits header files and man pages.
+	JLinkDebuggable JLinkProfile = "debuggable"
+)
+
+// ParseJLinkProfile parses a case-insensitive JLinkProfile, such as the value of $BP_JVM_JLINK_PROFILE.
+func ParseJLinkProfile(s string) (JLinkProfile, error) {
+	switch p := JLinkProfile(strings.ToLower(strings.TrimSpace(s))); p {
+	case JLinkMinimal, JLinkDebuggable:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown jlink profile %q, must be one of %s or %s", s, JLinkMinimal, JLinkDebuggable)
+	}
+}
+
+// Args returns the jlink arguments for the profile appropriate to javaVersion.  Numeric --compress levels are
+// deprecated from Java 21 in favour of zip-[0-9].
+func (p JLinkProfile) Args(javaVersion string) []string {
+	switch p {
+	case JLinkMinimal:
+		compress := "--compress=zip-6"
+		if IsBeforeJava21(javaVersion) {
+			compress = "--compress=2"
+		}
+		return []string{"--strip-debug", "--no-header-files", "--no-man-pages", compress}
+	case JLinkDebuggable:
+		return []string{"--no-header-files", "--no-man-pages"}
+	default:
+		return nil
+	}
+}
+
+// Merge returns args with the profile's arguments added, except for any options that args already contains.
+func (p JLinkProfile) Merge(args []string, javaVersion string) []string {
+	present := make(map[string]bool)
+	for _, a := range args {
+		present[jlinkOption(a)] = true
+	}
+
+	var merged []string
+	for _, a := range p.Args(javaVersion) {
+		if !present[jlinkOption(a)] {
+			merged = append(merged, a)
+		}
+	}
+
+	return append(merged, args...)
+}
+
+func jlinkOption(arg string) string {
+	o := strings.ToLower(strings.SplitN(arg, "=", 2)[0])
+	switch o {
+	case "-c":
+		return "--compress"
+	case "-g":
+		return "--strip-debug"
+	default:
+		return o
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testJLinkProfile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses profiles", func() {
+		Expect(libjvm.ParseJLinkProfile("minimal")).To(Equal(libjvm.JLinkMinimal))
+		Expect(libjvm.ParseJLinkProfile(" Debuggable ")).To(Equal(libjvm.JLinkDebuggable))
+
+		_, err := libjvm.ParseJLinkProfile("tiny")
+		Expect(err).To(MatchError(`unknown jlink profile "tiny", must be one of minimal or debuggable`))
+	})
+
+	it("uses numeric compression before Java 21", func() {
+		Expect(libjvm.JLinkMinimal.Args("17.0.9")).To(Equal([]string{"--strip-debug", "--no-header-files", "--no-man-pages", "--compress=2"}))
+	})
+
+	it("uses zip compression from Java 21", func() {
+		Expect(libjvm.JLinkMinimal.Args("21.0.1")).To(Equal([]string{"--strip-debug", "--no-header-files", "--no-man-pages", "--compress=zip-6"}))
+	})
+
+	it("keeps debug information", func() {
+		Expect(libjvm.JLinkDebuggable.Args("21.0.1")).To(Equal([]string{"--no-header-files", "--no-man-pages"}))
+	})
+
+	it("does not override explicit arguments", func() {
+		Expect(libjvm.JLinkMinimal.Merge([]string{"--compress=zip-9", "-G", "--bind-services"}, "21.0.1")).
+			To(Equal([]string{"--no-header-files", "--no-man-pages", "--compress=zip-9", "-G", "--bind-services"}))
+	})
+}
@@ -17,6 +17,7 @@
 package libjvm_test
 
 import (
+	"fmt"
 	"github.com/paketo-buildpacks/libpak/crush"
 	"github.com/paketo-buildpacks/libpak/effect"
 	"github.com/paketo-buildpacks/libpak/effect/mocks"
@@ -202,4 +203,181 @@ func testJLink(t *testing.T, context spec.G, it spec.S) {
 		Expect(e.Args).To(ContainElement("--output"))
 	})
 
+	context("application classes and JARs", func() {
+		it.Before(func() {
+			ctx.Application.Path = t.TempDir()
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "alpha.jar"), []byte{}, 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			ctx.Application.Path = ""
+		})
+
+		extract := func(layer libcnb.Layer) func(mock.Arguments) {
+			return func(args mock.Arguments) {
+				jre, err := os.Open("testdata/3aa01010c0d3592ea248c8353d60b361231fa9bf9a7479b4f06451fef3e64524/stub-jre-11.tar.gz")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(crush.Extract(jre, layer.Path, 1)).To(Succeed())
+			}
+		}
+
+		it("contributes jlink JRE with modules discovered by jdeps", func() {
+			exec := &mocks.Executor{}
+			j, err := libjvm.NewJLink(ctx.Application.Path, exec, []string{"--strip-debug"}, cl, LaunchContribution, false)
+			Expect(err).NotTo(HaveOccurred())
+			j.Logger = bard.NewLogger(io.Discard)
+			j.JavaVersion = "17.0.1"
+			j.ExtraModules = []string{"jdk.crypto.ec", "java.sql"}
+
+			layer, err := ctx.Layers.Layer("jlink")
+			Expect(err).NotTo(HaveOccurred())
+
+			exec.On("Execute", mock.MatchedBy(func(ex effect.Execution) bool {
+				return strings.HasSuffix(ex.Command, "jdeps")
+			})).Return(func(ex effect.Execution) error {
+				_, err := ex.Stdout.Write([]byte("java.base,java.sql\n"))
+				return err
+			})
+			exec.On("Execute", mock.MatchedBy(func(ex effect.Execution) bool {
+				return strings.HasSuffix(ex.Command, "jlink")
+			})).Run(extract(layer)).Return(nil)
+
+			_, err = j.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			e := exec.Calls[0].Arguments[0].(effect.Execution)
+			Expect(e.Args).To(Equal([]string{
+				"--print-module-deps", "--ignore-missing-deps", "--multi-release", "17",
+				filepath.Join(ctx.Application.Path, "BOOT-INF", "classes"),
+				filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "alpha.jar"),
+			}))
+
+			e = exec.Calls[1].Arguments[0].(effect.Execution)
+			Expect(e.Args).To(Equal([]string{"--strip-debug", "--add-modules", "java.base,java.sql,jdk.crypto.ec", "--output", layer.Path}))
+		})
+
+		it("falls back to all java.* modules when jdeps fails", func() {
+			exec := &mocks.Executor{}
+			j, err := libjvm.NewJLink(ctx.Application.Path, exec, []string{}, cl, LaunchContribution, false)
+			Expect(err).NotTo(HaveOccurred())
+			j.Logger = bard.NewLogger(io.Discard)
+
+			layer, err := ctx.Layers.Layer("jlink")
+			Expect(err).NotTo(HaveOccurred())
+
+			exec.On("Execute", mock.MatchedBy(func(ex effect.Execution) bool {
+				return strings.HasSuffix(ex.Command, "jdeps")
+			})).Return(fmt.Errorf("test-error"))
+			exec.On("Execute", mock.MatchedBy(func(ex effect.Execution) bool {
+				return strings.HasSuffix(ex.Command, "java")
+			})).Return(func(ex effect.Execution) error {
+				_, err := ex.Stdout.Write([]byte("java.base@17\njava.se@17\njdk.jfr@17\n"))
+				return err
+			})
+			exec.On("Execute", mock.MatchedBy(func(ex effect.Execution) bool {
+				return strings.HasSuffix(ex.Command, "jlink")
+			})).Run(extract(layer)).Return(nil)
+
+			_, err = j.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			e := exec.Calls[2].Arguments[0].(effect.Execution)
+			Expect(e.Args).To(Equal([]string{"--add-modules", "java.base,java.se", "--output", layer.Path}))
+		})
+
+		it("contributes jlink JRE with profile arguments", func() {
+			exec := &mocks.Executor{}
+			j, err := libjvm.NewJLink(ctx.Application.Path, exec, []string{"--bind-services"}, cl, LaunchContribution, false)
+			Expect(err).NotTo(HaveOccurred())
+			j.Logger = bard.NewLogger(io.Discard)
+			j.JavaVersion = "21.0.1"
+			j.Profile = libjvm.JLinkMinimal
+
+			layer, err := ctx.Layers.Layer("jlink")
+			Expect(err).NotTo(HaveOccurred())
+
+			exec.On("Execute", mock.MatchedBy(func(ex effect.Execution) bool {
+				return strings.HasSuffix(ex.Command, "jdeps")
+			})).Return(func(ex effect.Execution) error {
+				_, err := ex.Stdout.Write([]byte("java.base"))
+				return err
+			})
+			exec.On("Execute", mock.MatchedBy(func(ex effect.Execution) bool {
+				return strings.HasSuffix(ex.Command, "jlink")
+			})).Run(extract(layer)).Return(nil)
+
+			layer, err = j.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layer.Metadata).To(HaveKeyWithValue("jlink-profile", "minimal"))
+			e := exec.Calls[1].Arguments[0].(effect.Execution)
+			Expect(e.Args).To(Equal([]string{
+				"--strip-debug", "--no-header-files", "--no-man-pages", "--compress=zip-6", "--bind-services",
+				"--add-modules", "java.base", "--output", layer.Path,
+			}))
+		})
+
+		it("reuses jlink JRE unless the modules or JDK change", func() {
+			contribute := func(layer libcnb.Layer, digest string, modules string) (libcnb.Layer, *mocks.Executor) {
+				exec := &mocks.Executor{}
+				j, err := libjvm.NewJLink(ctx.Application.Path, exec, []string{}, cl, LaunchContribution, false)
+				Expect(err).NotTo(HaveOccurred())
+				j.Logger = bard.NewLogger(io.Discard)
+				j.JDKDigest = digest
+
+				exec.On("Execute", mock.MatchedBy(func(ex effect.Execution) bool {
+					return strings.HasSuffix(ex.Command, "jdeps")
+				})).Return(func(ex effect.Execution) error {
+					_, err := ex.Stdout.Write([]byte(modules))
+					return err
+				})
+				exec.On("Execute", mock.MatchedBy(func(ex effect.Execution) bool {
+					return strings.HasSuffix(ex.Command, "jlink")
+				})).Run(extract(layer)).Return(nil)
+
+				layer, err = j.Contribute(layer)
+				Expect(err).NotTo(HaveOccurred())
+				return layer, exec
+			}
+
+			layer, err := ctx.Layers.Layer("jlink")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, exec := contribute(layer, "digest-1", "java.base")
+			Expect(exec.Calls).To(HaveLen(2))
+			Expect(layer.Metadata).To(HaveKeyWithValue("jdk-digest", "digest-1"))
+			Expect(layer.Metadata).To(HaveKeyWithValue("jlink-modules", []interface{}{"java.base"}))
+
+			layer, exec = contribute(layer, "digest-1", "java.base")
+			Expect(exec.Calls).To(HaveLen(1))
+
+			layer, exec = contribute(layer, "digest-1", "java.base,java.sql")
+			Expect(exec.Calls).To(HaveLen(2))
+
+			_, exec = contribute(layer, "digest-2", "java.base,java.sql")
+			Expect(exec.Calls).To(HaveLen(2))
+		})
+
+		it("adds extra modules to user provided modules", func() {
+			exec := &mocks.Executor{}
+			j, err := libjvm.NewJLink(ctx.Application.Path, exec, []string{"--add-modules", "java.base"}, cl, LaunchContribution, true)
+			Expect(err).NotTo(HaveOccurred())
+			j.Logger = bard.NewLogger(io.Discard)
+			j.ExtraModules = []string{"jdk.crypto.ec"}
+
+			layer, err := ctx.Layers.Layer("jlink")
+			Expect(err).NotTo(HaveOccurred())
+
+			exec.On("Execute", mock.Anything).Run(extract(layer)).Return(nil)
+
+			_, err = j.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(exec.Calls).To(HaveLen(1))
+			e := exec.Calls[0].Arguments[0].(effect.Execution)
+			Expect(e.Args).To(Equal([]string{"--add-modules", "java.base", "--add-modules", "jdk.crypto.ec", "--output", layer.Path}))
+		})
+	})
 }
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// JVMFingerprint identifies the JVM installed at javaHome by its release file and the size of its module image.
+// Archives produced by one JVM, such as CDS archives, are only usable by a JVM with the same fingerprint.
+func JVMFingerprint(javaHome string) (string, error) {
+	h := sha256.New()
+
+	file := filepath.Join(javaHome, "release")
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+	h.Write(b)
+
+	file = filepath.Join(javaHome, "lib", "modules")
+	if fi, err := os.Stat(file); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("unable to stat %s\n%w", file, err)
+	} else if err == nil {
+		fmt.Fprintf(h, "%d", fi.Size())
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
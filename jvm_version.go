@@ -22,36 +22,60 @@ func NewJVMVersion(logger bard.Logger) JVMVersion {
 }
 
 func (j JVMVersion) GetJVMVersion(appPath string, cr libpak.ConfigurationResolver, dr libpak.DependencyResolver) (string, error) {
+	declared, inferred, err := versionFallbacks(cr)
+	if err != nil {
+		return "", err
+	}
+
 	version, explicit := cr.Resolve("BP_JVM_VERSION")
 	if explicit {
 		f := color.New(color.Faint)
 		j.Logger.Body(f.Sprintf("Using Java version %s from BP_JVM_VERSION", version))
-		return version, nil
+		return j.fallBack(dr, version, "BP_JVM_VERSION", declared), nil
+	}
+
+	p, _ := cr.Resolve("BP_JVM_VERSION_PATCH_POLICY")
+	policy, err := ParsePatchPolicy(p)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse $BP_JVM_VERSION_PATCH_POLICY\n%w", err)
 	}
 
-	sdkmanrcJavaVersion, err := readJavaVersionFromSDKMANRCFile(appPath)
+	sourceJavaVersion, source, err := ReadJavaVersionFromSources(appPath, j.Logger)
 	if err != nil {
-		return "", fmt.Errorf("unable to read Java version from SDMANRC file\n%w", err)
+		return "", fmt.Errorf("unable to read Java version from project files\n%w", err)
 	}
 
-	if len(sdkmanrcJavaVersion) > 0 {
-		sdkmanrcJavaMajorVersion := extractMajorVersion(sdkmanrcJavaVersion)
+	if len(sourceJavaVersion) > 0 {
+		sourceJavaConstraint := JavaVersionConstraint(sourceJavaVersion, policy)
 		f := color.New(color.Faint)
-		j.Logger.Body(f.Sprintf("Using Java version %s extracted from .sdkmanrc", sdkmanrcJavaMajorVersion))
-		return sdkmanrcJavaMajorVersion, nil
+		j.Logger.Body(f.Sprintf("Using Java version %s extracted from %s", sourceJavaConstraint, source))
+		return j.fallBack(dr, sourceJavaConstraint, source, declared), nil
 	}
 
-	mavenJavaVersion, err := readJavaVersionFromMavenMetadata(appPath)
+	jarJavaVersion, err := ReadJavaVersionFromJARs(appPath)
 	if err != nil {
-		return "", fmt.Errorf("unable to read Java version from Maven metadata\n%w", err)
+		return "", fmt.Errorf("unable to read Java version from JAR manifests\n%w", err)
 	}
 
-	if len(mavenJavaVersion) > 0 {
-		mavenJavaMajorVersion := extractMajorVersion(mavenJavaVersion)
-		retrieveNextAvailableJavaVersionIfMavenVersionNotAvailable(dr, &mavenJavaMajorVersion)
+	if len(jarJavaVersion.Version) > 0 {
+		jarJavaConstraint := JavaVersionConstraint(jarJavaVersion.Version, policy)
+		source := fmt.Sprintf("%s in %s", jarJavaVersion.Attribute, jarJavaVersion.Path)
 		f := color.New(color.Faint)
-		j.Logger.Body(f.Sprintf("Using Java version %s extracted from MANIFEST.MF", mavenJavaMajorVersion))
-		return mavenJavaMajorVersion, nil
+		j.Logger.Body(f.Sprintf("Using Java version %s extracted from %s", jarJavaConstraint, source))
+		return j.fallBack(dr, jarJavaConstraint, source, inferred), nil
+	}
+
+	classJavaVersion, err := ReadJavaVersionFromClasses(appPath)
+	if err != nil {
+		return "", fmt.Errorf("unable to read Java version from class files\n%w", err)
+	}
+
+	if len(classJavaVersion) > 0 && isLowerMajorVersion(version, classJavaVersion) {
+		j.Logger.Body(color.New(color.Faint, color.Bold).Sprintf(
+			"WARNING: buildpack default Java version %s is lower than Java %s required by application classes", version, classJavaVersion))
+		f := color.New(color.Faint)
+		j.Logger.Body(f.Sprintf("Using Java version %s extracted from class files", classJavaVersion))
+		return j.fallBack(dr, classJavaVersion, "class files", inferred), nil
 	}
 
 	f := color.New(color.Faint)
@@ -59,54 +83,78 @@ func (j JVMVersion) GetJVMVersion(appPath string, cr libpak.ConfigurationResolve
 	return version, nil
 }
 
-func retrieveNextAvailableJavaVersionIfMavenVersionNotAvailable(dr libpak.DependencyResolver, mavenJavaMajorVersion *string) {
-	_, jdkErr := dr.Resolve("jdk", *mavenJavaMajorVersion)
-	_, jreErr := dr.Resolve("jre", *mavenJavaMajorVersion)
-	if libpak.IsNoValidDependencies(jdkErr) && libpak.IsNoValidDependencies(jreErr) {
-		//	the buildpack does not provide the wanted JDK or JRE version - let's check if we can choose a more recent version
-		mavenJavaMajorVersionAsInt, _ := strconv.ParseInt(*mavenJavaMajorVersion, 10, 64)
-		nextVersionToEvaluate := mavenJavaMajorVersionAsInt + 1
-		_, jdkErr := dr.Resolve("jdk", strconv.FormatInt(nextVersionToEvaluate, 10))
-		_, jreErr := dr.Resolve("jre", strconv.FormatInt(nextVersionToEvaluate, 10))
-		if libpak.IsNoValidDependencies(jdkErr) && libpak.IsNoValidDependencies(jreErr) {
-			// we tried with the next major version, still no Java candidate, we are done trying.
-		} else {
-			*mavenJavaMajorVersion = strconv.FormatInt(nextVersionToEvaluate, 10)
-		}
+// versionFallbacks returns the fallbacks for versions declared by the application and for versions inferred from its
+// contents.  $BP_JVM_VERSION_FALLBACK applies to both.  Otherwise, declared versions must be provided exactly and
+// inferred versions fall back to the next available version.
+func versionFallbacks(cr libpak.ConfigurationResolver) (VersionFallback, VersionFallback, error) {
+	s, ok := cr.Resolve("BP_JVM_VERSION_FALLBACK")
+	if !ok || s == "" {
+		return VersionFallbackNone, VersionFallbackNextAvailable, nil
+	}
+
+	f, err := ParseVersionFallback(s)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to parse $BP_JVM_VERSION_FALLBACK\n%w", err)
+	}
+
+	return f, f, nil
+}
+
+// fallBack returns the version to use instead of the Java version from source, if the buildpack does not provide it.
+func (j JVMVersion) fallBack(dr libpak.DependencyResolver, version string, source string, fallback VersionFallback) string {
+	if fallback == VersionFallbackNone || isJavaVersionAvailable(dr, version) {
+		return version
+	}
+
+	next, ok := fallback.Next(dr, version)
+	if !ok {
+		return version
+	}
+
+	j.Logger.Body(color.New(color.Faint, color.Bold).Sprintf(
+		"WARNING: Java version %s from %s is not provided by this buildpack, using Java %s instead (%s)", version, source, next, fallback))
+	return next
+}
+
+// isLowerMajorVersion returns whether the major version of candidate is lower than required.  Versions that cannot be
+// compared, such as wildcards, are not lower.
+func isLowerMajorVersion(candidate string, required string) bool {
+	c, err := strconv.Atoi(extractMajorVersion(candidate))
+	if err != nil {
+		return false
 	}
+
+	r, err := strconv.Atoi(extractMajorVersion(required))
+	if err != nil {
+		return false
+	}
+
+	return c < r
 }
 
-func readJavaVersionFromSDKMANRCFile(appPath string) (string, error) {
+// GetJVMVendor returns the vendor of the Java version in .sdkmanrc, or an empty string if there is none or the version
+// is explicitly configured with $BP_JVM_VERSION.
+func (j JVMVersion) GetJVMVendor(appPath string, cr libpak.ConfigurationResolver) (string, error) {
+	if _, explicit := cr.Resolve("BP_JVM_VERSION"); explicit {
+		return "", nil
+	}
+
 	components, err := ReadSDKMANRC(filepath.Join(appPath, ".sdkmanrc"))
 	if err != nil && errors.Is(err, os.ErrNotExist) {
 		return "", nil
 	} else if err != nil {
-		return "", err
+		return "", fmt.Errorf("unable to read Java vendor from SDMANRC file\n%w", err)
 	}
 
 	for _, component := range components {
 		if component.Type == "java" {
-			return component.Version, nil
+			return component.Vendor, nil
 		}
 	}
 
 	return "", nil
 }
 
-func readJavaVersionFromMavenMetadata(appPath string) (string, error) {
-	manifest, err := NewManifest(appPath)
-	if err != nil {
-		return "", err
-	}
-
-	javaVersion, ok := manifest.Get("Build-Jdk-Spec")
-	if !ok {
-		javaVersion, _ = manifest.Get("Build-Jdk")
-	}
-
-	return javaVersion, nil
-}
-
 func extractMajorVersion(version string) string {
 	versionParts := strings.Split(version, ".")
 
@@ -17,6 +17,8 @@
 package libjvm_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
@@ -73,6 +75,33 @@ func testJVMVersion(t *testing.T, context spec.G, it spec.S) {
 		Expect(version).To(Equal("1.1.1"))
 	})
 
+	context("detecting JVM version", func() {
+		it.Before(func() {
+			Expect(prepareAppWithEntry(appPath, "Build-Jdk-Spec: 11")).ToNot(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			z := zip.NewWriter(b)
+			w, err := z.Create("META-INF/MANIFEST.MF")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte("Build-Jdk-Spec: 17\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(z.Close()).To(Succeed())
+
+			Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "lib"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "lib", "alpha.jar"), b.Bytes(), 0644)).To(Succeed())
+		})
+
+		it("from the application's manifest rather than its JARs", func() {
+			jvmVersion := libjvm.JVMVersion{Logger: logger}
+
+			cr, err := libpak.NewConfigurationResolver(buildpack, &logger)
+			Expect(err).ToNot(HaveOccurred())
+			version, err := jvmVersion.GetJVMVersion(appPath, cr, libpak.DependencyResolver{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal("11"))
+		})
+	})
+
 	context("detecting JVM version", func() {
 		it.Before(func() {
 			Expect(os.Setenv("BP_JVM_VERSION", "17")).To(Succeed())
@@ -147,6 +176,56 @@ func testJVMVersion(t *testing.T, context spec.G, it spec.S) {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(version).To(Equal("17"))
 		})
+
+		it("pins the patch version from .sdkmanrc file if $BP_JVM_VERSION_PATCH_POLICY is pinned", func() {
+			t.Setenv("BP_JVM_VERSION_PATCH_POLICY", "pinned")
+			jvmVersion := libjvm.JVMVersion{Logger: logger}
+
+			cr, err := libpak.NewConfigurationResolver(buildpack, &logger)
+			Expect(err).ToNot(HaveOccurred())
+			version, err := jvmVersion.GetJVMVersion(appPath, cr, libpak.DependencyResolver{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal("17.0.2"))
+		})
+
+		it("uses version ranges from project files", func() {
+			Expect(os.Remove(sdkmanrcFile)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(appPath, ".java-version"), []byte("17.0.*"), 0644)).To(Succeed())
+			jvmVersion := libjvm.JVMVersion{Logger: logger}
+
+			cr, err := libpak.NewConfigurationResolver(buildpack, &logger)
+			Expect(err).ToNot(HaveOccurred())
+			version, err := jvmVersion.GetJVMVersion(appPath, cr, libpak.DependencyResolver{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal("17.0.*"))
+		})
+
+		it("fails with an unknown $BP_JVM_VERSION_PATCH_POLICY", func() {
+			t.Setenv("BP_JVM_VERSION_PATCH_POLICY", "newest")
+			jvmVersion := libjvm.JVMVersion{Logger: logger}
+
+			cr, err := libpak.NewConfigurationResolver(buildpack, &logger)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = jvmVersion.GetJVMVersion(appPath, cr, libpak.DependencyResolver{})
+			Expect(err).To(MatchError(ContainSubstring(`unknown patch policy "newest"`)))
+		})
+
+		it("vendor from .sdkmanrc file", func() {
+			jvmVersion := libjvm.JVMVersion{Logger: logger}
+
+			cr, err := libpak.NewConfigurationResolver(buildpack, &logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(jvmVersion.GetJVMVendor(appPath, cr)).To(Equal("tem"))
+		})
+
+		it("ignores vendor from .sdkmanrc file if $BP_JVM_VERSION is set", func() {
+			t.Setenv("BP_JVM_VERSION", "11")
+			jvmVersion := libjvm.JVMVersion{Logger: logger}
+
+			cr, err := libpak.NewConfigurationResolver(buildpack, &logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(jvmVersion.GetJVMVendor(appPath, cr)).To(BeEmpty())
+		})
 	})
 
 	context("detecting JVM version", func() {
@@ -168,6 +247,52 @@ java=11.0.2-tem`), 0644)).To(Succeed())
 			Expect(version).To(Equal("17"))
 		})
 	})
+
+	context("detecting JVM version", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(appPath, "pom.xml"),
+				[]byte(`<project><properties><java.version>21</java.version></properties></project>`), 0644)).To(Succeed())
+			Expect(prepareAppWithEntry(appPath, "Build-Jdk: 1.8")).ToNot(HaveOccurred())
+		})
+
+		it("prefers project files over manifest", func() {
+			jvmVersion := libjvm.JVMVersion{Logger: logger}
+
+			cr, err := libpak.NewConfigurationResolver(buildpack, &logger)
+			Expect(err).ToNot(HaveOccurred())
+			version, err := jvmVersion.GetJVMVersion(appPath, cr, libpak.DependencyResolver{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal("21"))
+		})
+	})
+
+	context("detecting JVM version", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(appPath, "Alpha.class"), []byte{0xCA, 0xFE, 0xBA, 0xBE, 0x00, 0x00, 0x00, 61}, 0644)).To(Succeed())
+		})
+
+		it("from class files when default is lower", func() {
+			jvmVersion := libjvm.JVMVersion{Logger: logger}
+
+			cr, err := libpak.NewConfigurationResolver(buildpack, &logger)
+			Expect(err).ToNot(HaveOccurred())
+			version, err := jvmVersion.GetJVMVersion(appPath, cr, libpak.DependencyResolver{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal("17"))
+		})
+
+		it("uses default when it can run class files", func() {
+			buildpack.Metadata["configurations"] = []map[string]interface{}{{"name": "BP_JVM_VERSION", "default": "21"}}
+
+			jvmVersion := libjvm.JVMVersion{Logger: logger}
+
+			cr, err := libpak.NewConfigurationResolver(buildpack, &logger)
+			Expect(err).ToNot(HaveOccurred())
+			version, err := jvmVersion.GetJVMVersion(appPath, cr, libpak.DependencyResolver{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal("21"))
+		})
+	})
 }
 
 func prepareAppWithEntry(appPath, entry string) error {
@@ -30,7 +30,7 @@ import (
 )
 
 // NewManifest reads the <APP>/META-INF/MANIFEST.MF file if it exists, normalizing it into the standard properties
-// form.
+// form.  Use NewJARManifest to keep per-entry sections and repeated attributes.
 func NewManifest(applicationPath string) (*properties.Properties, error) {
 	file := filepath.Join(applicationPath, "META-INF", "MANIFEST.MF")
 
@@ -55,16 +55,21 @@ func NewManifestFromJAR(jarFilePath string) (*properties.Properties, error) {
 	}
 	defer jarFile.Close()
 
+	return newManifestFromZip(&jarFile.Reader, jarFilePath)
+}
+
+func newManifestFromZip(z *zip.Reader, source string) (*properties.Properties, error) {
 	// look for the MANIFEST
-	manifestFile, err := jarFile.Open("META-INF/MANIFEST.MF")
+	manifestFile, err := z.Open("META-INF/MANIFEST.MF")
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return &properties.Properties{}, nil
 		}
-		return nil, fmt.Errorf("unable to read MANIFEST.MF in %s\n%w", jarFilePath, err)
+		return nil, fmt.Errorf("unable to read MANIFEST.MF in %s\n%w", source, err)
 	}
+	defer manifestFile.Close()
 
-	return loadManifest(manifestFile, jarFilePath)
+	return loadManifest(manifestFile, source)
 }
 
 func loadManifest(reader io.Reader, source string) (*properties.Properties, error) {
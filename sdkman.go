@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"unicode"
+
+	"github.com/paketo-buildpacks/libpak"
 )
 
 // SDKInfo represents the information from each line in the `.sdkmanrc` file
@@ -74,3 +77,102 @@ func ReadSDKMANRC(path string) ([]SDKInfo, error) {
 
 	return sdks, nil
 }
+
+// SDKMANVendors maps the vendor identifiers used by SDKMAN to the names that identify a distribution in buildpack and
+// dependency names.
+var SDKMANVendors = map[string][]string{
+	"albba":   {"dragonwell"},
+	"amzn":    {"corretto", "amazon"},
+	"bisheng": {"bisheng"},
+	"graal":   {"graalvm"},
+	"graalce": {"graalvm"},
+	"jbr":     {"jetbrains"},
+	"kona":    {"kona"},
+	"librca":  {"liberica", "bellsoft"},
+	"mandrel": {"mandrel"},
+	"ms":      {"microsoft"},
+	"nik":     {"liberica", "bellsoft"},
+	"open":    {"openjdk"},
+	"oracle":  {"oracle"},
+	"sapmchn": {"sapmachine"},
+	"sem":     {"semeru"},
+	"tem":     {"temurin", "adoptium"},
+	"trava":   {"trava"},
+	"zulu":    {"zulu", "azul"},
+}
+
+// MatchesVendor returns whether any of the candidate names identify the SDKMAN vendor, and whether the vendor is
+// known.  A candidate identifies the vendor if one of its words is a name of the vendor and none is a name of another
+// vendor.  As most distributions are named OpenJDK, that name does not rule out other vendors, so that
+// "Microsoft OpenJDK" identifies ms but not open.
+func MatchesVendor(vendor string, candidates ...string) (bool, bool) {
+	names, ok := SDKMANVendors[strings.ToLower(vendor)]
+	if !ok {
+		return false, false
+	}
+
+	own := map[string]bool{}
+	for _, n := range names {
+		own[n] = true
+	}
+
+	others := map[string]bool{}
+	for id, v := range SDKMANVendors {
+		if id == "open" {
+			continue
+		}
+		for _, n := range v {
+			if !own[n] {
+				others[n] = true
+			}
+		}
+	}
+
+	for _, c := range candidates {
+		matched := false
+		for _, w := range strings.FieldsFunc(strings.ToLower(c), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if others[w] {
+				matched = false
+				break
+			}
+			matched = matched || own[w]
+		}
+
+		if matched {
+			return true, true
+		}
+	}
+
+	return false, true
+}
+
+// PreferVendor restricts the JDK and JRE dependencies of each Java version to those of the SDKMAN vendor, if any of them
+// are provided by that vendor.  Dependencies of Java versions the vendor does not provide are kept, so that a version is
+// still resolved and the vendor checked against it.  Dependencies are returned unchanged for an unknown vendor.
+func PreferVendor(deps []libpak.BuildpackDependency, vendor string) []libpak.BuildpackDependency {
+	if vendor == "" {
+		return deps
+	}
+
+	key := func(d libpak.BuildpackDependency) string {
+		return d.ID + "@" + strings.SplitN(d.Version, ".", 2)[0]
+	}
+
+	provided := map[string]bool{}
+	for _, d := range deps {
+		if matched, _ := MatchesVendor(vendor, d.ID, d.Name); matched {
+			provided[key(d)] = true
+		}
+	}
+
+	var preferred []libpak.BuildpackDependency
+	for _, d := range deps {
+		if matched, _ := MatchesVendor(vendor, d.ID, d.Name); matched || !provided[key(d)] || (d.ID != "jdk" && d.ID != "jre") {
+			preferred = append(preferred, d)
+		}
+	}
+
+	return preferred
+}
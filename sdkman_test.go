@@ -24,6 +24,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/paketo-buildpacks/libjvm"
+	"github.com/paketo-buildpacks/libpak"
 	"github.com/sclevine/spec"
 )
 
@@ -156,4 +157,73 @@ java=17.0.2-tem
 			}))
 		})
 	})
+
+	context("vendors", func() {
+		it("matches names of the vendor's distribution", func() {
+			matched, known := libjvm.MatchesVendor("tem", "paketo-buildpacks/adoptium", "Eclipse Temurin JRE")
+			Expect(matched).To(BeTrue())
+			Expect(known).To(BeTrue())
+		})
+
+		it("does not match names of another distribution", func() {
+			matched, known := libjvm.MatchesVendor("graal", "paketo-buildpacks/bellsoft-liberica", "BellSoft Liberica JRE")
+			Expect(matched).To(BeFalse())
+			Expect(known).To(BeTrue())
+		})
+
+		it("matches whole names only", func() {
+			matched, _ := libjvm.MatchesVendor("kona", "paketo-buildpacks/konajdk", "Konajdk JRE")
+			Expect(matched).To(BeFalse())
+		})
+
+		it("does not match distributions that also name another vendor", func() {
+			matched, _ := libjvm.MatchesVendor("open", "paketo-buildpacks/microsoft-openjdk", "Microsoft OpenJDK JDK")
+			Expect(matched).To(BeFalse())
+
+			matched, _ = libjvm.MatchesVendor("ms", "paketo-buildpacks/microsoft-openjdk", "Microsoft OpenJDK JDK")
+			Expect(matched).To(BeTrue())
+
+			matched, _ = libjvm.MatchesVendor("open", "OpenJDK JRE")
+			Expect(matched).To(BeTrue())
+		})
+
+		it("does not know unrecognised vendors", func() {
+			_, known := libjvm.MatchesVendor("foo", "Foo JRE")
+			Expect(known).To(BeFalse())
+		})
+
+		it("prefers dependencies of the vendor", func() {
+			deps := []libpak.BuildpackDependency{
+				{ID: "jre", Name: "BellSoft Liberica JRE"},
+				{ID: "jre", Name: "GraalVM JRE"},
+				{ID: "jdk", Name: "BellSoft Liberica JDK"},
+				{ID: "native-image-svm", Name: "BellSoft Liberica NIK"},
+			}
+
+			Expect(libjvm.PreferVendor(deps, "graal")).To(Equal([]libpak.BuildpackDependency{
+				{ID: "jre", Name: "GraalVM JRE"},
+				{ID: "jdk", Name: "BellSoft Liberica JDK"},
+				{ID: "native-image-svm", Name: "BellSoft Liberica NIK"},
+			}))
+		})
+
+		it("prefers dependencies of the vendor only for the Java versions it provides", func() {
+			deps := []libpak.BuildpackDependency{
+				{ID: "jre", Name: "BellSoft Liberica JRE", Version: "17.0.8"},
+				{ID: "jre", Name: "BellSoft Liberica JRE", Version: "21.0.1"},
+				{ID: "jre", Name: "GraalVM JRE", Version: "21.0.1"},
+			}
+
+			Expect(libjvm.PreferVendor(deps, "graal")).To(Equal([]libpak.BuildpackDependency{
+				{ID: "jre", Name: "BellSoft Liberica JRE", Version: "17.0.8"},
+				{ID: "jre", Name: "GraalVM JRE", Version: "21.0.1"},
+			}))
+		})
+
+		it("does not change dependencies if the vendor is not provided", func() {
+			deps := []libpak.BuildpackDependency{{ID: "jre", Name: "BellSoft Liberica JRE"}}
+
+			Expect(libjvm.PreferVendor(deps, "zulu")).To(Equal(deps))
+		})
+	})
 }
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package libjvm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// BaseThreadCount is an estimate of the threads started by the JVM and frameworks in addition to request threads.
+	BaseThreadCount = 50
+
+	// ReactiveThreadCount is an estimate of the event loop and bounded elastic threads used by reactive servers.
+	ReactiveThreadCount = 50
+
+	// DefaultServletThreadCount is the default maximum request thread pool size of Tomcat and Jetty.
+	DefaultServletThreadCount = 200
+
+	// ThreadCountEvidenceSeparator separates the evidence of a thread count estimate made at build time.
+	ThreadCountEvidenceSeparator = "\n"
+)
+
+var (
+	JettyJARRE    = regexp.MustCompile("^jetty-server-.*\\.jar$")
+	NettyJARRE    = regexp.MustCompile("^(reactor-netty(-http)?|netty-transport)-.*\\.jar$")
+	TomcatJARRE   = regexp.MustCompile("^tomcat-embed-core-.*\\.jar$")
+	WebFluxJARRE  = regexp.MustCompile("^spring-webflux-.*\\.jar$")
+	TomcatThreads = []string{"server.tomcat.threads.max", "server.tomcat.max-threads"}
+)
+
+// ThreadCountEstimate is a thread count estimated from an application and the evidence used to estimate it.
+type ThreadCountEstimate struct {
+	Count    int
+	Evidence []string
+}
+
+// EstimateThreadCount estimates the number of threads an application will use from the server framework that it
+// includes. If no framework is found, an empty estimate is returned.
+func EstimateThreadCount(applicationPath string) (ThreadCountEstimate, error) {
+	var (
+		e                             ThreadCountEstimate
+		jetty, netty, tomcat, webflux string
+	)
+
+	m, err := NewManifest(applicationPath)
+	if err != nil {
+		return ThreadCountEstimate{}, fmt.Errorf("unable to read manifest\n%w", err)
+	}
+	if s, ok := m.Get("Start-Class"); ok {
+		e.Evidence = append(e.Evidence, fmt.Sprintf("Start-Class %s", s))
+	} else if s, ok := m.Get("Main-Class"); ok {
+		e.Evidence = append(e.Evidence, fmt.Sprintf("Main-Class %s", s))
+	}
+
+	if err := filepath.WalkDir(applicationPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		n := d.Name()
+		switch {
+		case JettyJARRE.MatchString(n):
+			jetty = n
+		case NettyJARRE.MatchString(n):
+			netty = n
+		case TomcatJARRE.MatchString(n):
+			tomcat = n
+		case WebFluxJARRE.MatchString(n):
+			webflux = n
+		}
+		return nil
+	}); err != nil {
+		return ThreadCountEstimate{}, fmt.Errorf("unable to walk %s\n%w", applicationPath, err)
+	}
+
+	switch {
+	case tomcat != "" || jetty != "":
+		threads := DefaultServletThreadCount
+		if tomcat != "" {
+			e.Evidence = append(e.Evidence, tomcat)
+
+			t, source, err := readTomcatThreads(applicationPath)
+			if err != nil {
+				return ThreadCountEstimate{}, err
+			}
+			if source != "" {
+				threads = t
+				e.Evidence = append(e.Evidence, fmt.Sprintf("%s=%d in %s", TomcatThreads[0], t, source))
+			}
+		} else {
+			e.Evidence = append(e.Evidence, jetty)
+		}
+		e.Count = BaseThreadCount + threads
+	case webflux != "" || netty != "":
+		for _, s := range []string{webflux, netty} {
+			if s != "" {
+				e.Evidence = append(e.Evidence, s)
+			}
+		}
+		e.Count = BaseThreadCount + ReactiveThreadCount
+	default:
+		return ThreadCountEstimate{}, nil
+	}
+
+	return e, nil
+}
+
+// readTomcatThreads reads the maximum Tomcat request thread count from Spring Boot configuration files, returning the
+// file it was found in or an empty string if it is not configured.
+func readTomcatThreads(applicationPath string) (int, string, error) {
+	for _, d := range []string{"", "config", filepath.Join("BOOT-INF", "classes"), filepath.Join("WEB-INF", "classes")} {
+		file := filepath.Join(applicationPath, d, "application.properties")
+		if p, err := properties.LoadFile(file, properties.UTF8); err == nil {
+			for _, k := range TomcatThreads {
+				if s, ok := p.Get(k); ok {
+					t, err := strconv.Atoi(strings.TrimSpace(s))
+					if err != nil {
+						return 0, "", fmt.Errorf("unable to convert %s=%s in %s to integer\n%w", k, s, file, err)
+					}
+					return t, file, nil
+				}
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return 0, "", fmt.Errorf("unable to read %s\n%w", file, err)
+		}
+
+		for _, n := range []string{"application.yml", "application.yaml"} {
+			file := filepath.Join(applicationPath, d, n)
+			t, ok, err := readYAMLInt(file, TomcatThreads...)
+			if err != nil {
+				return 0, "", err
+			}
+			if ok {
+				return t, file, nil
+			}
+		}
+	}
+
+	return 0, "", nil
+}
+
+// readYAMLInt reads the first of the dotted keys found in any document of a YAML file, matching both nested and
+// flattened keys.
+func readYAMLInt(file string, keys ...string) (int, bool, error) {
+	in, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	d := yaml.NewDecoder(in)
+	for {
+		var doc map[string]interface{}
+		if err := d.Decode(&doc); errors.Is(err, io.EOF) {
+			return 0, false, nil
+		} else if err != nil {
+			return 0, false, fmt.Errorf("unable to decode %s\n%w", file, err)
+		}
+
+		for _, k := range keys {
+			if v, ok := lookupYAML(doc, strings.Split(k, ".")); ok {
+				t, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(v)))
+				if err != nil {
+					return 0, false, fmt.Errorf("unable to convert %s=%v in %s to integer\n%w", k, v, file, err)
+				}
+				return t, true, nil
+			}
+		}
+	}
+}
+
+func lookupYAML(doc map[string]interface{}, key []string) (interface{}, bool) {
+	for i := len(key); i > 0; i-- {
+		v, ok := doc[strings.Join(key[:i], ".")]
+		if !ok {
+			continue
+		}
+		if i == len(key) {
+			return v, true
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			if r, ok := lookupYAML(m, key[i:]); ok {
+				return r, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package libjvm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testThreadCount(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = t.TempDir()
+		Expect(os.MkdirAll(filepath.Join(path, "BOOT-INF", "lib"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(path, "BOOT-INF", "classes"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(path, "META-INF"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "META-INF", "MANIFEST.MF"), []byte("Start-Class: com.example.App\n"), 0644)).To(Succeed())
+	})
+
+	it("returns no estimate without a framework", func() {
+		Expect(libjvm.EstimateThreadCount(path)).To(Equal(libjvm.ThreadCountEstimate{}))
+	})
+
+	it("estimates reactive applications", func() {
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "lib", "spring-webflux-6.1.0.jar"), []byte{}, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "lib", "reactor-netty-http-1.1.0.jar"), []byte{}, 0644)).To(Succeed())
+
+		Expect(libjvm.EstimateThreadCount(path)).To(Equal(libjvm.ThreadCountEstimate{
+			Count:    libjvm.BaseThreadCount + libjvm.ReactiveThreadCount,
+			Evidence: []string{"Start-Class com.example.App", "spring-webflux-6.1.0.jar", "reactor-netty-http-1.1.0.jar"},
+		}))
+	})
+
+	it("estimates Tomcat applications", func() {
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "lib", "tomcat-embed-core-10.1.0.jar"), []byte{}, 0644)).To(Succeed())
+
+		Expect(libjvm.EstimateThreadCount(path)).To(Equal(libjvm.ThreadCountEstimate{
+			Count:    libjvm.BaseThreadCount + libjvm.DefaultServletThreadCount,
+			Evidence: []string{"Start-Class com.example.App", "tomcat-embed-core-10.1.0.jar"},
+		}))
+	})
+
+	it("reads Tomcat threads from application.properties", func() {
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "lib", "tomcat-embed-core-10.1.0.jar"), []byte{}, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "classes", "application.properties"),
+			[]byte("server.tomcat.threads.max=400\n"), 0644)).To(Succeed())
+
+		e, err := libjvm.EstimateThreadCount(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(e.Count).To(Equal(libjvm.BaseThreadCount + 400))
+		Expect(e.Evidence).To(ContainElement(
+			"server.tomcat.threads.max=400 in " + filepath.Join(path, "BOOT-INF", "classes", "application.properties")))
+	})
+
+	it("reads Tomcat threads from application.yml", func() {
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "lib", "tomcat-embed-core-10.1.0.jar"), []byte{}, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "classes", "application.yml"),
+			[]byte("spring:\n  application:\n    name: test\n---\nserver:\n  tomcat:\n    threads.max: 100\n"), 0644)).To(Succeed())
+
+		e, err := libjvm.EstimateThreadCount(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(e.Count).To(Equal(libjvm.BaseThreadCount + 100))
+	})
+
+	it("returns error if Tomcat threads is not an integer", func() {
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "lib", "tomcat-embed-core-10.1.0.jar"), []byte{}, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "application.properties"), []byte("server.tomcat.max-threads=many\n"), 0644)).To(Succeed())
+
+		_, err := libjvm.EstimateThreadCount(path)
+		Expect(err).To(MatchError(HavePrefix("unable to convert server.tomcat.max-threads=many")))
+	})
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/effect"
+
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+// DefaultTrainingRunTimeout is how long a training run may take before it is stopped and the build fails.
+const DefaultTrainingRunTimeout = 5 * time.Minute
+
+// TrainingRun contributes a layer created by running the application once at build time with the JVM from JVMLayer.
+// The layer is keyed by the JDK digest and the metadata of the JVM layer rather than the JVM's files, which are not
+// present on rebuild if the JVM layer is launch-only, and by the application and training arguments, so that it is only
+// reused while all are unchanged.  The metadata of the JVM layer captures everything it is built from, such as the
+// modules and arguments of a jlink JRE.
+type TrainingRun struct {
+	ApplicationPath  string
+	Executor         effect.Executor
+	JDKDigest        string
+	JVMLayer         string
+	LayerContributor libpak.LayerContributor
+	Logger           bard.Logger
+	TrainingArgs     []string
+}
+
+func NewTrainingRun(name string, applicationPath string, exec effect.Executor, jvmLayer string, jdkDigest string, trainingArgs []string, info libcnb.BuildpackInfo) TrainingRun {
+	return TrainingRun{
+		ApplicationPath: applicationPath,
+		Executor:        exec,
+		JDKDigest:       jdkDigest,
+		JVMLayer:        jvmLayer,
+		LayerContributor: libpak.NewLayerContributor(
+			name,
+			info,
+			libcnb.LayerTypes{
+				Launch: true,
+			},
+		),
+		TrainingArgs: trainingArgs,
+	}
+}
+
+// Contribute contributes layer with f unless it can be reused.  f is called with the fingerprint of the JVM, which
+// is used at launch to check that the JVM has not changed.
+func (t TrainingRun) Contribute(layer libcnb.Layer, f func(layer libcnb.Layer, jvm string) (libcnb.Layer, error)) (libcnb.Layer, error) {
+	t.LayerContributor.Logger = t.Logger
+
+	application, err := count.Fingerprint(t.ApplicationPath)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to fingerprint application\n%w", err)
+	}
+
+	// the JVM layer is contributed, and its metadata written, before the training run
+	layers := libcnb.Layers{Path: filepath.Dir(layer.Path)}
+	jvmLayer, err := layers.Layer(t.JVMLayer)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to read JVM layer %s\n%w", t.JVMLayer, err)
+	}
+	jvmMetadata := jvmLayer.Metadata
+	if jvmMetadata == nil {
+		jvmMetadata = map[string]interface{}{}
+	}
+
+	t.LayerContributor.ExpectedMetadata = map[string]interface{}{
+		"application":   application,
+		"jdk-digest":    t.JDKDigest,
+		"jvm-metadata":  jvmMetadata,
+		"training-args": t.TrainingArgs,
+	}
+
+	return t.LayerContributor.Contribute(layer, func() (libcnb.Layer, error) {
+		jvm, err := JVMFingerprint(t.javaHome(layer))
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to fingerprint JVM\n%w", err)
+		}
+
+		return f(layer, jvm)
+	})
+}
+
+// Run runs the application with the JVM options in args followed by the training arguments.
+func (t TrainingRun) Run(layer libcnb.Layer, args ...string) error {
+	return t.Executor.Execute(effect.Execution{
+		Command: filepath.Join(t.javaHome(layer), "bin", "java"),
+		Args:    append(args, t.TrainingArgs...),
+		Dir:     t.ApplicationPath,
+		Stdout:  t.Logger.BodyWriter(),
+		Stderr:  t.Logger.BodyWriter(),
+	})
+}
+
+func (t TrainingRun) javaHome(layer libcnb.Layer) string {
+	return filepath.Join(filepath.Dir(layer.Path), t.JVMLayer)
+}
+
+// DefaultTrainingArgs returns the arguments for a training run of a Spring Boot application, identified by a
+// Start-Class, which is stopped once its context has been refreshed.  The class path includes the manifest's
+// Class-Path.  Other applications have no exit condition, so no arguments are returned and training arguments must be
+// configured.
+func DefaultTrainingArgs(applicationPath string) ([]string, error) {
+	m, err := NewManifest(applicationPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read manifest\n%w", err)
+	}
+
+	main, ok := m.Get("Main-Class")
+	if !ok {
+		return nil, nil
+	}
+
+	if _, ok := m.Get("Start-Class"); !ok {
+		return nil, nil
+	}
+
+	classPath := []string{applicationPath}
+	if cp, ok := m.Get("Class-Path"); ok {
+		for _, e := range strings.Fields(cp) {
+			classPath = append(classPath, filepath.Join(applicationPath, e))
+		}
+	}
+
+	return []string{"-Dspring.context.exit=onRefresh", "-cp", strings.Join(classPath, string(filepath.ListSeparator)), main}, nil
+}
+
+// TrainingRunExecutor runs training runs, stopping any that have not exited within Timeout.
+type TrainingRunExecutor struct {
+	Timeout time.Duration
+}
+
+func (t TrainingRunExecutor) Execute(execution effect.Execution) error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, execution.Command, execution.Args...)
+	cmd.Dir = execution.Dir
+	if len(execution.Env) > 0 {
+		cmd.Env = execution.Env
+	}
+	cmd.Stdin = execution.Stdin
+	cmd.Stdout = execution.Stdout
+	cmd.Stderr = execution.Stderr
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("training run did not exit within %s", t.Timeout)
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package libjvm_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/effect"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/libjvm"
+)
+
+func testTrainingRun(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		appPath = t.TempDir()
+	})
+
+	context("DefaultTrainingArgs", func() {
+		it("returns no arguments without Main-Class", func() {
+			Expect(libjvm.DefaultTrainingArgs(appPath)).To(BeNil())
+		})
+
+		it("returns no arguments for applications other than Spring Boot", func() {
+			Expect(os.MkdirAll(filepath.Join(appPath, "META-INF"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(appPath, "META-INF", "MANIFEST.MF"),
+				[]byte("Main-Class: test.Main\n"), 0644)).To(Succeed())
+
+			Expect(libjvm.DefaultTrainingArgs(appPath)).To(BeNil())
+		})
+
+		it("stops Spring Boot applications once refreshed", func() {
+			Expect(os.MkdirAll(filepath.Join(appPath, "META-INF"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(appPath, "META-INF", "MANIFEST.MF"),
+				[]byte("Main-Class: org.springframework.boot.loader.launch.JarLauncher\nStart-Class: test.Application\n"), 0644)).To(Succeed())
+
+			Expect(libjvm.DefaultTrainingArgs(appPath)).To(Equal([]string{
+				"-Dspring.context.exit=onRefresh", "-cp", appPath, "org.springframework.boot.loader.launch.JarLauncher",
+			}))
+		})
+
+		it("includes Class-Path", func() {
+			Expect(os.MkdirAll(filepath.Join(appPath, "META-INF"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(appPath, "META-INF", "MANIFEST.MF"),
+				[]byte("Main-Class: org.springframework.boot.loader.launch.JarLauncher\nStart-Class: test.Application\nClass-Path: lib/a.jar lib/b.jar\n"), 0644)).To(Succeed())
+
+			Expect(libjvm.DefaultTrainingArgs(appPath)).To(Equal([]string{
+				"-Dspring.context.exit=onRefresh", "-cp",
+				strings.Join([]string{
+					appPath,
+					filepath.Join(appPath, "lib", "a.jar"),
+					filepath.Join(appPath, "lib", "b.jar"),
+				}, ":"),
+				"org.springframework.boot.loader.launch.JarLauncher",
+			}))
+		})
+	})
+
+	context("TrainingRunExecutor", func() {
+		it("stops training runs that do not exit", func() {
+			err := libjvm.TrainingRunExecutor{Timeout: 100 * time.Millisecond}.Execute(effect.Execution{
+				Command: "sleep",
+				Args:    []string{"10"},
+			})
+			Expect(err).To(MatchError("training run did not exit within 100ms"))
+		})
+
+		it("runs training runs", func() {
+			Expect(libjvm.TrainingRunExecutor{Timeout: time.Minute}.Execute(effect.Execution{Command: "true"})).To(Succeed())
+		})
+	})
+}
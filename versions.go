@@ -17,12 +17,19 @@
 package libjvm
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/Masterminds/semver/v3"
 )
 
 var Java9, _ = semver.NewVersion("9")
+var Java13, _ = semver.NewVersion("13")
 var Java17, _ = semver.NewVersion("17")
 var Java18, _ = semver.NewVersion("18")
+var Java21, _ = semver.NewVersion("21")
+var Java24, _ = semver.NewVersion("24")
 
 func IsBeforeJava9(candidate string) bool {
 	v, err := semver.NewVersion(candidate)
@@ -33,6 +40,15 @@ func IsBeforeJava9(candidate string) bool {
 	return v.LessThan(Java9)
 }
 
+func IsBeforeJava13(candidate string) bool {
+	v, err := semver.NewVersion(candidate)
+	if err != nil {
+		return false
+	}
+
+	return v.LessThan(Java13)
+}
+
 func IsBeforeJava17(candidate string) bool {
 	v, err := semver.NewVersion(candidate)
 	if err != nil {
@@ -50,3 +66,95 @@ func IsBeforeJava18(candidate string) bool {
 
 	return v.LessThan(Java18)
 }
+
+func IsBeforeJava21(candidate string) bool {
+	v, err := semver.NewVersion(candidate)
+	if err != nil {
+		return false
+	}
+
+	return v.LessThan(Java21)
+}
+
+func IsBeforeJava24(candidate string) bool {
+	v, err := semver.NewVersion(candidate)
+	if err != nil {
+		return false
+	}
+
+	return v.LessThan(Java24)
+}
+
+// PatchPolicy determines how a Java version declared by an application is resolved to a dependency.
+type PatchPolicy string
+
+const (
+	// PatchPolicyLatest resolves the latest patch release of the declared Java version, so that security fixes are
+	// always applied.
+	PatchPolicyLatest PatchPolicy = "latest"
+
+	// PatchPolicyPinned resolves exactly the declared Java version.
+	PatchPolicyPinned PatchPolicy = "pinned"
+)
+
+// ParsePatchPolicy parses a case-insensitive PatchPolicy, defaulting to PatchPolicyLatest if s is empty.
+func ParsePatchPolicy(s string) (PatchPolicy, error) {
+	switch p := PatchPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return PatchPolicyLatest, nil
+	case PatchPolicyLatest, PatchPolicyPinned:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown patch policy %q, must be one of %s or %s", s, PatchPolicyLatest, PatchPolicyPinned)
+	}
+}
+
+// javaVersionPattern matches the numeric components of a Java version, such as 17.0.6 in 17.0.6+10 or 1.8.0_292.
+var javaVersionPattern = regexp.MustCompile(`^\d+(?:[._]\d+)*`)
+
+// NormalizeJavaVersion converts a Java version, such as 1.8.0_292 or 17.0.6+10, to the semantic version of a
+// dependency, such as 8.0.292 or 17.0.6.  Version ranges are returned unchanged.
+func NormalizeJavaVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if IsJavaVersionRange(version) {
+		return version
+	}
+
+	m := javaVersionPattern.FindString(version)
+	if m == "" {
+		return version
+	}
+
+	parts := strings.FieldsFunc(m, func(r rune) bool { return r == '.' || r == '_' })
+	if parts[0] == "1" && len(parts) > 1 {
+		parts = parts[1:]
+	}
+	if len(parts) > 3 {
+		parts = parts[:3]
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// IsJavaVersionRange returns whether version is a constraint that matches a range of versions, such as 17.0.* or
+// >=17.0.5, rather than a single version.
+func IsJavaVersionRange(version string) bool {
+	if !strings.ContainsAny(version, "<>=~^*xX|,") && !strings.Contains(version, " - ") {
+		return false
+	}
+
+	_, err := semver.NewConstraint(version)
+	return err == nil
+}
+
+// JavaVersionConstraint returns the constraint that resolves the dependency for a Java version declared by an
+// application.  Ranges are used as declared.  A single version is used exactly with PatchPolicyPinned, and otherwise
+// matches the latest patch release of its major version.
+func JavaVersionConstraint(version string, policy PatchPolicy) string {
+	v := NormalizeJavaVersion(version)
+	if policy == PatchPolicyPinned || IsJavaVersionRange(v) {
+		return v
+	}
+
+	return extractMajorVersion(v)
+}
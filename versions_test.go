@@ -37,6 +37,13 @@ func testVersions(t *testing.T, context spec.G, it spec.S) {
 		Expect(libjvm.IsBeforeJava9("")).To(BeFalse())
 	})
 
+	it("determines whether a version is before Java 13", func() {
+		Expect(libjvm.IsBeforeJava13("11.0.0")).To(BeTrue())
+		Expect(libjvm.IsBeforeJava13("13.0.0")).To(BeFalse())
+		Expect(libjvm.IsBeforeJava13("17.0.0")).To(BeFalse())
+		Expect(libjvm.IsBeforeJava13("")).To(BeFalse())
+	})
+
 	it("determins whether a version is before Java 18", func() {
 		Expect(libjvm.IsBeforeJava18("17.0.0")).To(BeTrue())
 		Expect(libjvm.IsBeforeJava18("18.0.0")).To(BeFalse())
@@ -50,4 +57,53 @@ func testVersions(t *testing.T, context spec.G, it spec.S) {
 		Expect(libjvm.IsBeforeJava17("18.0.0")).To(BeFalse())
 		Expect(libjvm.IsBeforeJava17("")).To(BeFalse())
 	})
+
+	it("determines whether a version is before Java 21", func() {
+		Expect(libjvm.IsBeforeJava21("17.0.0")).To(BeTrue())
+		Expect(libjvm.IsBeforeJava21("21.0.0")).To(BeFalse())
+		Expect(libjvm.IsBeforeJava21("22.0.0")).To(BeFalse())
+		Expect(libjvm.IsBeforeJava21("")).To(BeFalse())
+	})
+
+	it("determines whether a version is before Java 24", func() {
+		Expect(libjvm.IsBeforeJava24("21.0.0")).To(BeTrue())
+		Expect(libjvm.IsBeforeJava24("24.0.0")).To(BeFalse())
+		Expect(libjvm.IsBeforeJava24("25.0.0")).To(BeFalse())
+		Expect(libjvm.IsBeforeJava24("")).To(BeFalse())
+	})
+
+	it("parses patch policies", func() {
+		Expect(libjvm.ParsePatchPolicy("")).To(Equal(libjvm.PatchPolicyLatest))
+		Expect(libjvm.ParsePatchPolicy("Latest")).To(Equal(libjvm.PatchPolicyLatest))
+		Expect(libjvm.ParsePatchPolicy("pinned")).To(Equal(libjvm.PatchPolicyPinned))
+
+		_, err := libjvm.ParsePatchPolicy("newest")
+		Expect(err).To(HaveOccurred())
+	})
+
+	it("normalizes Java versions", func() {
+		Expect(libjvm.NormalizeJavaVersion("17")).To(Equal("17"))
+		Expect(libjvm.NormalizeJavaVersion("17.0.6+10")).To(Equal("17.0.6"))
+		Expect(libjvm.NormalizeJavaVersion("1.8")).To(Equal("8"))
+		Expect(libjvm.NormalizeJavaVersion("1.8.0_292")).To(Equal("8.0.292"))
+		Expect(libjvm.NormalizeJavaVersion("11.0.20.1")).To(Equal("11.0.20"))
+		Expect(libjvm.NormalizeJavaVersion("17.0.6 (Eclipse Adoptium)")).To(Equal("17.0.6"))
+		Expect(libjvm.NormalizeJavaVersion(">=17.0.5")).To(Equal(">=17.0.5"))
+	})
+
+	it("determines whether a version is a range", func() {
+		Expect(libjvm.IsJavaVersionRange("17.0.*")).To(BeTrue())
+		Expect(libjvm.IsJavaVersionRange(">=17.0.5")).To(BeTrue())
+		Expect(libjvm.IsJavaVersionRange("17.0.5 - 17.0.8")).To(BeTrue())
+		Expect(libjvm.IsJavaVersionRange("17.0.6")).To(BeFalse())
+		Expect(libjvm.IsJavaVersionRange("17.0.6 (Eclipse Adoptium)")).To(BeFalse())
+	})
+
+	it("creates constraints for Java versions", func() {
+		Expect(libjvm.JavaVersionConstraint("17.0.6+10", libjvm.PatchPolicyLatest)).To(Equal("17"))
+		Expect(libjvm.JavaVersionConstraint("1.8.0_292", libjvm.PatchPolicyLatest)).To(Equal("8"))
+		Expect(libjvm.JavaVersionConstraint("17.0.6+10", libjvm.PatchPolicyPinned)).To(Equal("17.0.6"))
+		Expect(libjvm.JavaVersionConstraint("17.0.*", libjvm.PatchPolicyLatest)).To(Equal("17.0.*"))
+		Expect(libjvm.JavaVersionConstraint(">=17.0.5", libjvm.PatchPolicyPinned)).To(Equal(">=17.0.5"))
+	})
 }